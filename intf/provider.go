@@ -18,14 +18,15 @@ const (
 type ProviderName string
 
 const (
-	ProviderNameConfigViper       ProviderName = "config-viper"
-	ProviderNameLoggerZerolog     ProviderName = "logger-zerolog"
-	ProviderNameRedisRedigo       ProviderName = "redis-redigo"
-	ProviderNameDbSqlBoilerMysql  ProviderName = "db-sqlboiler-mysql"
-	ProviderNameDbSqlBoilerSqlite ProviderName = "db-sqlboiler-sqlite3"
-	ProviderNameDbSqlxMysql       ProviderName = "db-sqlx-mysql"
-	ProviderNameDbSquirrelMysql   ProviderName = "db-squirrel-mysql"
-	ProviderNameMqRabbitMq        ProviderName = "mq-rabbitmq"
+	ProviderNameConfigViper         ProviderName = "config-viper"
+	ProviderNameLoggerZerolog       ProviderName = "logger-zerolog"
+	ProviderNameRedisRedigo         ProviderName = "redis-redigo"
+	ProviderNameDbSqlBoilerMysql    ProviderName = "db-sqlboiler-mysql"
+	ProviderNameDbSqlBoilerSqlite   ProviderName = "db-sqlboiler-sqlite3"
+	ProviderNameDbSqlBoilerPostgres ProviderName = "db-sqlboiler-postgres"
+	ProviderNameDbSqlxMysql         ProviderName = "db-sqlx-mysql"
+	ProviderNameDbSquirrelMysql     ProviderName = "db-squirrel-mysql"
+	ProviderNameMqRabbitMq          ProviderName = "mq-rabbitmq"
 )
 
 // Capability 能力提供者
@@ -39,3 +40,9 @@ type Capability struct {
 type Provider interface {
 	Init(args ...any) error // 初始化
 }
+
+// ShardRouter 根据租户id选择数据源的名字, 名字为空时使用缺省数据源
+type ShardRouter interface {
+	Route(tenantId int64) string
+	Reload(data []byte) error // 热更新路由规则, data为json格式的路由配置, 配置了shard.config_key时由shard.Watch在配置中心变化时调用
+}
@@ -2,4 +2,6 @@ package intf
 
 type ConfigProvider interface {
 	Unmarshal(configVar any, key ...string) error
+	GetApp() string
+	GetEnv() string
 }
@@ -1,7 +1,9 @@
 package intf
 
 import (
+	"context"
 	"database/sql"
+	"github.com/hdget/hdsdk/v2/lib/pagination"
 	"github.com/hdget/hdsdk/v2/protobuf"
 	"github.com/jmoiron/sqlx"
 )
@@ -10,12 +12,23 @@ type Sqlizer interface {
 	ToSql() (string, []interface{}, error)
 }
 
+// ContextSqlizer 生成SQL时需要ctx的sqlizer, e,g: 根据ctx生成租户条件的sqltemplate, DbBuilderClient的X方法会传入调用时的ctx
+type ContextSqlizer interface {
+	Sqlizer
+	ToSqlContext(ctx context.Context) (string, []any, error)
+}
+
 type DbProvider interface {
 	Provider
 	My() DbClient
 	Master() DbClient
-	Slave(i int) DbClient
+	Slave(i int) DbClient                // 索引越界时返回nil
+	Reader(ctx context.Context) DbClient // 在健康的从库间负载均衡, 没有从库或者ctx要求读主库时返回主库
+	Writer() DbClient                    // 主库, 没有配置master时为缺省数据库
 	By(name string) DbClient
+	ForTenant(ctx context.Context) DbClient // 按ctx中的租户id路由到sdk.mysql.items中的数据源, 没有配置路由或者没有匹配时返回缺省数据源
+	Router() ShardRouter                    // 租户路由, 没有配置时返回nil
+	Stats() map[string]sql.DBStats          // 连接池统计信息, key为default, master, slave.<index>, item.<name>
 }
 
 type DbClient interface {
@@ -26,14 +39,27 @@ type DbClient interface {
 	Select(dest interface{}, query string, args ...interface{}) error
 	Rebind(query string) string
 	Close() error
+
+	// ctx没有设置deadline时, Exec/Get/Select使用配置的缺省查询超时时间
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
 
 type SqlxDbProvider interface {
 	Provider
 	My() SqlxDbClient
 	Master() SqlxDbClient
-	Slave(i int) SqlxDbClient
+	Slave(i int) SqlxDbClient                // 索引越界时返回nil
+	Reader(ctx context.Context) SqlxDbClient // 在健康的从库间负载均衡, 没有从库或者ctx要求读主库时返回主库
+	Writer() SqlxDbClient                    // 主库, 没有配置master时为缺省数据库
 	By(name string) SqlxDbClient
+	ForTenant(ctx context.Context) SqlxDbClient // 按ctx中的租户id路由到sdk.mysql.items中的数据源, 没有配置路由或者没有匹配时返回缺省数据源
+	Router() ShardRouter                        // 租户路由, 没有配置时返回nil
+	Stats() map[string]sql.DBStats              // 连接池统计信息, key为default, master, slave.<index>, item.<name>
 }
 
 type SqlxDbClient interface {
@@ -41,6 +67,8 @@ type SqlxDbClient interface {
 	NamedExec(query string, arg interface{}) (sql.Result, error)
 	Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
 	Beginx() (*sqlx.Tx, error)
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
 	Db() *sqlx.DB
 }
 
@@ -48,15 +76,23 @@ type DbBuilderProvider interface {
 	Provider
 	My() DbBuilderClient
 	Master() DbBuilderClient
-	Slave(i int) DbBuilderClient
+	Slave(i int) DbBuilderClient                // 索引越界时返回nil
+	Reader(ctx context.Context) DbBuilderClient // 在健康的从库间负载均衡, 没有从库或者ctx要求读主库时返回主库
+	Writer() DbBuilderClient                    // 主库, 没有配置master时为缺省数据库
 	By(name string) DbBuilderClient
-	Set(sqlizer Sqlizer)
+	ForTenant(ctx context.Context) DbBuilderClient // 按ctx中的租户id路由到sdk.mysql.items中的数据源, 没有配置路由或者没有匹配时返回缺省数据源
+	Router() ShardRouter                           // 租户路由, 没有配置时返回nil
+	Stats() map[string]sql.DBStats                 // 连接池统计信息, key为default, master, slave.<index>, item.<name>
+	With(sqlizer Sqlizer) DbBuilderProvider        // 返回绑定sqlizer的新实例, 不修改原实例, 可以在并发请求中使用
 }
 
+// DbBuilderClient 绑定了sqlizer的客户端, 不可修改
 type DbBuilderClient interface {
-	ToSql() (string, []any, error)
-	XGet(v any) error
-	XSelect(v any, args ...*protobuf.ListParam) error
-	XCount() (int64, error)
-	XQuery(args ...*protobuf.ListParam) (*sqlx.Rows, error)
+	ToSql() (string, []any, error) // sqlizer为ContextSqlizer时没有ctx, 需要ctx生成的SQL使用X方法执行
+	XGet(ctx context.Context, v any) error
+	XSelect(ctx context.Context, v any, args ...*protobuf.ListParam) error
+	XSelectKeyset(ctx context.Context, v any, ks *pagination.Keyset) (*pagination.KeysetPage, error) // 游标分页, builder中不能再有OrderBy
+	XCount(ctx context.Context) (int64, error)                                                       // 直接执行COUNT语句的sqlizer
+	XCountDerived(ctx context.Context) (int64, error)                                                // 列表查询加OrderBy之前的SelectBuilder去掉分页后作为子查询计算总数
+	XQuery(ctx context.Context, args ...*protobuf.ListParam) (*sqlx.Rows, error)
 }
@@ -1,19 +1,78 @@
 package intf
 
-import "github.com/hdget/hdsdk/v2/protobuf"
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2/protobuf"
+	"iter"
+	"time"
+)
 
 type RedisCommand struct {
 	Name string
 	Args []interface{}
 }
 
+// RedisHashEntry HSCAN返回的field/value
+type RedisHashEntry struct {
+	Field string
+	Value string
+}
+
+// RedisZSetMember ZSCAN返回的member/score
+type RedisZSetMember struct {
+	Member string
+	Score  float64
+}
+
+// RedisStreamEntry 流中的一条消息, 消息已被删除时Fields为nil
+type RedisStreamEntry struct {
+	Id     string
+	Fields map[string]string
+}
+
+// RedisStream XREAD/XREADGROUP返回的某个流中的消息
+type RedisStream struct {
+	Name    string
+	Entries []*RedisStreamEntry
+}
+
+// RedisPendingEntry XPENDING返回的已投递但未确认的消息
+type RedisPendingEntry struct {
+	Id            string
+	Consumer      string
+	Idle          time.Duration
+	DeliveryCount int64
+}
+
+// RedisSetOption SET命令的选项, Expire>0时设置过期时间, KeepTtl保留原有的过期时间
+// NX只在key不存在时设置, XX只在key存在时设置
+type RedisSetOption struct {
+	Expire  time.Duration
+	KeepTtl bool
+	NX      bool
+	XX      bool
+}
+
+// RedisGeoLocation 地理位置, Dist只在GeoSearch时返回, 单位与查询时的单位一致
+type RedisGeoLocation struct {
+	Name      string
+	Longitude float64
+	Latitude  float64
+	Dist      float64
+}
+
 type RedisProvider interface {
 	Provider
 	My() RedisClient
 	By(string) RedisClient
+	ForTenant(ctx context.Context) RedisClient // 按ctx中的租户id路由到sdk.redis.items中的实例, 没有配置路由或者没有匹配时返回缺省实例
+	Router() ShardRouter                       // 租户路由, 没有配置时返回nil
 }
 
 type RedisClient interface {
+	// Primary 返回所有命令都在主库执行的client, 没有配置从库时返回自身
+	Primary() RedisClient
+
 	// general purpose
 	Del(key string) error
 	Dels(keys []string) error
@@ -25,10 +84,15 @@ type RedisClient interface {
 	Ttl(key string) (int64, error)
 	Pipeline(commands []*RedisCommand) (reply interface{}, err error)
 	Ping() error
+	MGet(keys ...string) ([][]byte, error) // 不存在的key对应的值为nil
+	MSet(values map[string]any) error
 
 	// Set operations
 	Set(key string, value interface{}) error
 	SetEx(key string, value interface{}, expire int) error
+	SetNX(key string, value any, expire int) (bool, error)                // key不存在时设置, expire<=0表示不过期, 返回是否设置成功
+	SetWith(key string, value any, option *RedisSetOption) (bool, error)  // 按选项设置, 因为NX/XX条件未满足而未设置时返回false
+	SetGet(key string, value any, option *RedisSetOption) ([]byte, error) // 设置并返回旧值, 旧值不存在时返回redis.ErrNil
 
 	// Get operations
 	Get(key string) ([]byte, error)
@@ -50,6 +114,8 @@ type RedisClient interface {
 	HDel(key string, field interface{}) (int, error)
 	HDels(key string, fields []interface{}) (int, error)
 	HLen(key string) (int, error)
+	HIncrBy(key string, field string, increment int64) (int64, error)
+	HIncrByFloat(key string, field string, increment float64) (float64, error)
 
 	// set
 	SIsMember(key string, member interface{}) (bool, error)
@@ -61,24 +127,79 @@ type RedisClient interface {
 	SMembers(key string) ([]string, error)
 
 	// zset
-	ZAdd(key string, score int64, member interface{}) error
+	ZAdd(key string, score float64, member interface{}) error
 	ZCard(key string) (int, error)
 	ZRange(key string, min, max int64) ([]string, error)
 	ZRemRangeByScore(key string, min, max interface{}) error
 	ZRangeByScore(key string, min, max interface{}, withScores bool, list *protobuf.ListParam) ([]string, error)
-	ZScore(key string, member interface{}) (int64, error)
+	ZScore(key string, member interface{}) (float64, error) // 成员不存在时返回redis.ErrNil
 	ZInterstore(newKey string, keys ...interface{}) (int64, error)
-	ZIncrBy(key string, increment int64, member interface{}) error
+	ZIncrBy(key string, increment float64, member interface{}) (float64, error)
 	ZRem(destKey string, members ...interface{}) (int64, error)
+	ZRevRange(key string, start, stop int64) ([]string, error)
+	ZRank(key string, member any) (int64, error)    // 成员不存在时返回redis.ErrNil
+	ZRevRank(key string, member any) (int64, error) // 成员不存在时返回redis.ErrNil
+	ZCount(key string, min, max any) (int64, error)
 
 	// list
 	LPush(key string, values ...any) error
 	RPush(key string, values ...any) error
 	RPop(key string) ([]byte, error)
+	LPop(key string) ([]byte, error)                                     // 列表为空时返回redis.ErrNil
+	BLPop(timeout time.Duration, keys ...string) (string, []byte, error) // 返回弹出元素的key和值, 超时返回redis.ErrNil
+	BRPop(timeout time.Duration, keys ...string) (string, []byte, error) // 返回弹出元素的key和值, 超时返回redis.ErrNil
+	LRange(key string, start, end int64) ([][]byte, error)
+	LTrim(key string, start, end int64) error
+	LRem(key string, count int64, value any) (int64, error)
 	LRangeInt64(key string, start, end int64) ([]int64, error)
 	LRangeString(key string, start, end int64) ([]string, error)
 	LLen(key string) (int64, error)
+
+	// geo
+	GeoAdd(key string, locations ...*RedisGeoLocation) (int64, error)
+	GeoPos(key string, members ...string) ([]*RedisGeoLocation, error)         // 不存在的成员对应的位置为nil
+	GeoDist(key string, member1, member2 string, unit string) (float64, error) // 成员不存在时返回redis.ErrNil
+	GeoSearch(key string, longitude, latitude float64, radius float64, unit string, count int64) ([]*RedisGeoLocation, error)
+
+	// hyperloglog
+	PfAdd(key string, elements ...any) (bool, error)
+	PfCount(keys ...string) (int64, error)
+	PfMerge(destKey string, sourceKeys ...string) error
+
+	// bitmap
+	SetBit(key string, offset int64, value int) (int, error)
+	GetBit(key string, offset int64) (int, error)
+	BitCount(key string, byteRange ...int64) (int64, error)   // byteRange为空时统计整个位图, 否则为start, end字节范围
+	BitField(key string, operations ...any) ([]*int64, error) // OVERFLOW FAIL导致未执行的操作结果为nil
+
+	// script
 	Eval(scriptContent string, keys []interface{}, args []interface{}) (interface{}, error)
+	EvalSha(sha string, keys []interface{}, args []interface{}) (interface{}, error)
+	ScriptLoad(scriptContent string) (string, error)
+
+	// scan, match为空表示匹配所有, count<=0使用redis缺省值
+	Scan(match string, count int) iter.Seq2[string, error]
+	HScan(key string, match string, count int) iter.Seq2[*RedisHashEntry, error]
+	SScan(key string, match string, count int) iter.Seq2[string, error]
+	ZScan(key string, match string, count int) iter.Seq2[*RedisZSetMember, error]
+	DeleteByPattern(pattern string, batchSize int) (int64, error)
+
+	// stream, streams为key=>起始id, block<=0表示不阻塞, maxLen<=0表示不裁剪
+	XAdd(key string, fields map[string]any, maxLen int64) (string, error)
+	XLen(key string) (int64, error)
+	XRange(key string, start, end string, count int64) ([]*RedisStreamEntry, error)
+	XDel(key string, ids ...string) (int64, error)
+	XTrim(key string, maxLen int64) (int64, error)
+	XRead(streams map[string]string, count int64, block time.Duration) ([]*RedisStream, error)
+	XReadGroup(group, consumer string, streams map[string]string, count int64, block time.Duration, noAck bool) ([]*RedisStream, error)
+	XAck(key, group string, ids ...string) (int64, error)
+	XPending(key, group string, start, end string, count int64, consumer ...string) ([]*RedisPendingEntry, error)
+	XClaim(key, group, consumer string, minIdle time.Duration, ids ...string) ([]*RedisStreamEntry, error)
+	XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int64) (string, []*RedisStreamEntry, error)
+	XGroupCreate(key, group, start string, mkStream bool) error
+	XGroupDestroy(key, group string) error
+	XGroupSetId(key, group, id string) error
+	XGroupDelConsumer(key, group, consumer string) (int64, error)
 
 	// redis bloom
 	BfExists(key string, item string) (exists bool, err error)
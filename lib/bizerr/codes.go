@@ -10,6 +10,7 @@ const (
 
 // define common error code
 const (
-	_               = ErrCodeModuleRoot + iota // unknown error code
-	ErrCodeInternal                            // internal error
+	_                      = ErrCodeModuleRoot + iota // unknown error code
+	ErrCodeInternal                                   // internal error
+	ErrCodeTooManyRequests                            // too many requests
 )
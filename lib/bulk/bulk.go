@@ -0,0 +1,212 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Writer 批量写入, rows为结构体切片或者map切片, 按占位符数量限制分批执行多行INSERT, 返回每批影响的行数
+// ctx中有db.InTx开启的事务时在事务中执行
+type Writer interface {
+	Insert(ctx context.Context, table string, rows any) ([]int64, error)
+	// InsertIgnore 忽略主键或唯一键冲突的行, MySQL为INSERT IGNORE, SQLite为INSERT OR IGNORE, PostgreSQL为ON CONFLICT DO NOTHING
+	InsertIgnore(ctx context.Context, table string, rows any) ([]int64, error)
+	// Upsert 冲突时更新updateColumns, updateColumns为空时更新除conflictColumns以外的所有列
+	// MySQL为ON DUPLICATE KEY UPDATE, 不需要conflictColumns, SQLite和PostgreSQL为ON CONFLICT, 必须指定conflictColumns
+	Upsert(ctx context.Context, table string, rows any, conflictColumns []string, updateColumns ...string) ([]int64, error)
+	// LoadData 只支持MySQL, 通过LOAD DATA LOCAL INFILE流式写入, 服务端需要开启local_infile
+	LoadData(ctx context.Context, table string, rows any) (int64, error)
+}
+
+type writerImpl struct {
+	*writerOption
+	client  intf.DbClient
+	dialect *dialect
+}
+
+// insertMode INSERT语句的类型
+type insertMode int
+
+const (
+	insertModeNormal insertMode = iota
+	insertModeIgnore
+	insertModeUpsert
+)
+
+func New(client intf.DbClient, options ...Option) Writer {
+	o := &writerOption{}
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &writerImpl{
+		writerOption: o,
+		client:       client,
+		dialect:      getDialect(client),
+	}
+}
+
+func (w *writerImpl) Insert(ctx context.Context, table string, rows any) ([]int64, error) {
+	return w.write(ctx, table, rows, insertModeNormal, nil, nil)
+}
+
+func (w *writerImpl) InsertIgnore(ctx context.Context, table string, rows any) ([]int64, error) {
+	return w.write(ctx, table, rows, insertModeIgnore, nil, nil)
+}
+
+func (w *writerImpl) Upsert(ctx context.Context, table string, rows any, conflictColumns []string, updateColumns ...string) ([]int64, error) {
+	if w.dialect.name != driverMysql && len(conflictColumns) == 0 {
+		return nil, errors.New("conflict columns required")
+	}
+	return w.write(ctx, table, rows, insertModeUpsert, conflictColumns, updateColumns)
+}
+
+func (w *writerImpl) write(ctx context.Context, table string, rows any, mode insertMode, conflictColumns, updateColumns []string) ([]int64, error) {
+	columns, values, err := w.getRows(rows)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	prefix, suffix := w.buildClauses(table, columns, mode, conflictColumns, updateColumns)
+	rowPlaceholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	// ctx中有db.InTx开启的事务时在事务中写入
+	exec := db.Executor(ctx, w.client)
+	batchSize := w.getBatchSize(len(columns))
+	affected := make([]int64, 0, (len(values)+batchSize-1)/batchSize)
+	for start := 0; start < len(values); start += batchSize {
+		end := min(start+batchSize, len(values))
+
+		var sb strings.Builder
+		sb.WriteString(prefix)
+		args := make([]any, 0, (end-start)*len(columns))
+		for i := start; i < end; i++ {
+			if i > start {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(rowPlaceholder)
+			args = append(args, values[i]...)
+		}
+		sb.WriteString(suffix)
+
+		result, err := exec.ExecContext(ctx, exec.Rebind(sb.String()), args...)
+		if err != nil {
+			return affected, errors.Wrapf(err, "bulk write, table: %s, batch: %d", table, len(affected))
+		}
+
+		n, err := result.RowsAffected()
+		if err != nil {
+			return affected, errors.Wrap(err, "get affected rows")
+		}
+		affected = append(affected, n)
+	}
+	return affected, nil
+}
+
+func (w *writerImpl) getRows(rows any) ([]string, [][]any, error) {
+	columns, values, err := extractRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, values, err = filterColumns(columns, values, w.columns, w.omitColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(values) > 0 && len(columns) == 0 {
+		return nil, nil, errors.New("no column to insert")
+	}
+	return columns, values, nil
+}
+
+// buildClauses 生成VALUES之前和之后的部分
+func (w *writerImpl) buildClauses(table string, columns []string, mode insertMode, conflictColumns, updateColumns []string) (string, string) {
+	d := w.dialect
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = d.quote(column)
+	}
+
+	verb := "INSERT INTO"
+	var suffix string
+	switch mode {
+	case insertModeIgnore:
+		switch d.name {
+		case driverMysql:
+			verb = "INSERT IGNORE INTO"
+		case driverSqlite:
+			verb = "INSERT OR IGNORE INTO"
+		default:
+			suffix = " ON CONFLICT DO NOTHING"
+		}
+	case insertModeUpsert:
+		if len(updateColumns) == 0 {
+			updateColumns = excludeColumns(columns, conflictColumns)
+		}
+
+		sets := make([]string, len(updateColumns))
+		for i, column := range updateColumns {
+			if d.name == driverMysql {
+				sets[i] = fmt.Sprintf("%s = VALUES(%s)", d.quote(column), d.quote(column))
+			} else {
+				sets[i] = fmt.Sprintf("%s = excluded.%s", d.quote(column), d.quote(column))
+			}
+		}
+
+		if d.name == driverMysql {
+			// 没有需要更新的列时更新为自身, 等同于DO NOTHING, 不用INSERT IGNORE避免忽略其他错误
+			if len(sets) == 0 {
+				sets = []string{fmt.Sprintf("%s = %s", d.quote(columns[0]), d.quote(columns[0]))}
+			}
+			suffix = " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+		} else {
+			quotedConflicts := make([]string, len(conflictColumns))
+			for i, column := range conflictColumns {
+				quotedConflicts[i] = d.quote(column)
+			}
+
+			if len(sets) == 0 {
+				suffix = fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", strings.Join(quotedConflicts, ", "))
+			} else {
+				suffix = fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(quotedConflicts, ", "), strings.Join(sets, ", "))
+			}
+		}
+	}
+
+	prefix := fmt.Sprintf("%s %s (%s) VALUES ", verb, d.quote(table), strings.Join(quotedColumns, ", "))
+	return prefix, suffix
+}
+
+// getBatchSize 每批的行数不能超过占位符数量限制
+func (w *writerImpl) getBatchSize(numColumns int) int {
+	maxPlaceholders := w.maxPlaceholders
+	if maxPlaceholders <= 0 {
+		maxPlaceholders = w.dialect.maxPlaceholders
+	}
+
+	batchSize := max(maxPlaceholders/numColumns, 1)
+	if w.batchSize > 0 && w.batchSize < batchSize {
+		batchSize = w.batchSize
+	}
+	return batchSize
+}
+
+func excludeColumns(columns, excludes []string) []string {
+	excluded := make(map[string]struct{}, len(excludes))
+	for _, column := range excludes {
+		excluded[column] = struct{}{}
+	}
+
+	var kept []string
+	for _, column := range columns {
+		if _, ok := excluded[column]; !ok {
+			kept = append(kept, column)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,57 @@
+package bulk
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"strings"
+)
+
+// dialect 不同数据库的批量写入差异, 通过dbClient的driver名字判断
+type dialect struct {
+	name            string
+	maxPlaceholders int
+}
+
+// driverNamer sqlx.DB和sqlx.Tx实现了该接口
+type driverNamer interface {
+	DriverName() string
+}
+
+const (
+	driverMysql    = "mysql"
+	driverSqlite   = "sqlite"
+	driverPostgres = "postgres"
+)
+
+var (
+	// 每条语句最多的占位符数量, SQLite 3.32之前为999
+	driver2maxPlaceholders = map[string]int{
+		driverMysql:    65535,
+		driverSqlite:   32766,
+		driverPostgres: 65535,
+	}
+)
+
+// getDialect 无法获取driver时按MySQL处理
+func getDialect(client intf.DbClient) *dialect {
+	name := driverMysql
+	if v, ok := client.(driverNamer); ok {
+		switch driverName := v.DriverName(); {
+		case strings.HasPrefix(driverName, "sqlite"):
+			name = driverSqlite
+		case driverName == "postgres" || driverName == "pgx":
+			name = driverPostgres
+		}
+	}
+
+	return &dialect{
+		name:            name,
+		maxPlaceholders: driver2maxPlaceholders[name],
+	}
+}
+
+func (d *dialect) quote(identifier string) string {
+	if d.name == driverMysql {
+		return "`" + identifier + "`"
+	}
+	return `"` + identifier + `"`
+}
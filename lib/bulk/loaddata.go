@@ -0,0 +1,132 @@
+package bulk
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/pkg/errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	loadDataNull       = `\N`
+	loadDataTimeLayout = "2006-01-02 15:04:05.999999"
+)
+
+var (
+	loadDataEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+)
+
+func (w *writerImpl) LoadData(ctx context.Context, table string, rows any) (int64, error) {
+	if w.dialect.name != driverMysql {
+		return 0, errors.New("load data only supports mysql")
+	}
+
+	columns, values, err := w.getRows(rows)
+	if err != nil || len(values) == 0 {
+		return 0, err
+	}
+
+	// 通过io.Pipe边编码边发送, 不需要在内存中生成完整的文件
+	handlerName := "bulk_" + uuid.NewString()
+	mysql.RegisterReaderHandler(handlerName, func() io.Reader {
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(writeTsv(pw, values))
+		}()
+		return pr
+	})
+	defer mysql.DeregisterReaderHandler(handlerName)
+
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = w.dialect.quote(column)
+	}
+
+	query := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET utf8mb4 FIELDS TERMINATED BY '\\t' ESCAPED BY '\\\\' LINES TERMINATED BY '\\n' (%s)",
+		handlerName, w.dialect.quote(table), strings.Join(quotedColumns, ", "))
+	result, err := db.Executor(ctx, w.client).ExecContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrapf(err, "load data, table: %s", table)
+	}
+	return result.RowsAffected()
+}
+
+// writeTsv 按LOAD DATA的缺省格式编码, 字段以\t分隔, 行以\n分隔, NULL为\N
+func writeTsv(out io.Writer, values [][]any) error {
+	bw := bufio.NewWriter(out)
+	for _, row := range values {
+		for i, value := range row {
+			if i > 0 {
+				if err := bw.WriteByte('\t'); err != nil {
+					return err
+				}
+			}
+
+			field, err := formatField(value)
+			if err != nil {
+				return err
+			}
+
+			if _, err = bw.WriteString(field); err != nil {
+				return err
+			}
+		}
+
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func formatField(value any) (string, error) {
+	// sqlboiler的null类型等实现了driver.Valuer
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return loadDataNull, nil
+	case string:
+		return loadDataEscaper.Replace(v), nil
+	case []byte:
+		if v == nil {
+			return loadDataNull, nil
+		}
+		return loadDataEscaper.Replace(string(v)), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format(loadDataTimeLayout), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return loadDataNull, nil
+			}
+			return formatField(rv.Elem().Interface())
+		}
+		return loadDataEscaper.Replace(fmt.Sprint(v)), nil
+	}
+}
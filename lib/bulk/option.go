@@ -0,0 +1,36 @@
+package bulk
+
+type Option func(*writerOption)
+
+type writerOption struct {
+	batchSize       int      // 每批最多的行数, 0表示只受占位符数量限制
+	maxPlaceholders int      // 每条语句最多的占位符数量, 0表示使用数据库的缺省限制
+	columns         []string // 只写入指定的列
+	omitColumns     []string // 不写入的列, e,g: 自增主键
+}
+
+func WithBatchSize(batchSize int) Option {
+	return func(o *writerOption) {
+		o.batchSize = batchSize
+	}
+}
+
+func WithMaxPlaceholders(maxPlaceholders int) Option {
+	return func(o *writerOption) {
+		o.maxPlaceholders = maxPlaceholders
+	}
+}
+
+// WithColumns 只写入指定的列, 缺省写入所有列
+func WithColumns(columns ...string) Option {
+	return func(o *writerOption) {
+		o.columns = columns
+	}
+}
+
+// WithOmitColumns 不写入指定的列, e,g: 自增主键id
+func WithOmitColumns(columns ...string) Option {
+	return func(o *writerOption) {
+		o.omitColumns = columns
+	}
+}
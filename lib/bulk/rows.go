@@ -0,0 +1,214 @@
+package bulk
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structField 结构体中对应数据库列的字段
+type structField struct {
+	column string
+	index  []int
+}
+
+var (
+	type2fields sync.Map // reflect.Type => []*structField
+)
+
+// extractRows 将结构体切片或者map切片转换为列名和每行的值
+func extractRows(rows any) ([]string, [][]any, error) {
+	v := reflect.ValueOf(rows)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return nil, nil, errors.New("rows must be slice of struct or map")
+	}
+
+	if v.Len() == 0 {
+		return nil, nil, nil
+	}
+
+	elemType := v.Type().Elem()
+	for elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	switch elemType.Kind() {
+	case reflect.Struct:
+		return extractStructRows(v, elemType)
+	case reflect.Map:
+		if elemType.Key().Kind() != reflect.String {
+			return nil, nil, errors.New("map key must be string")
+		}
+		return extractMapRows(v)
+	default:
+		return nil, nil, errors.Errorf("unsupported row type: %s", elemType)
+	}
+}
+
+func extractStructRows(v reflect.Value, elemType reflect.Type) ([]string, [][]any, error) {
+	fields := getStructFields(elemType)
+	if len(fields) == 0 {
+		return nil, nil, errors.Errorf("no column found in struct: %s", elemType)
+	}
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+
+	values := make([][]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				return nil, nil, errors.Errorf("nil row at index: %d", i)
+			}
+			elem = elem.Elem()
+		}
+
+		row := make([]any, len(fields))
+		for j, f := range fields {
+			row[j] = elem.FieldByIndex(f.index).Interface()
+		}
+		values[i] = row
+	}
+	return columns, values, nil
+}
+
+// extractMapRows 列名为所有行的key的并集, 按字母顺序排列, 行中缺少的列值为nil
+func extractMapRows(v reflect.Value) ([]string, [][]any, error) {
+	elems := make([]reflect.Value, v.Len())
+	seen := make(map[string]struct{})
+	var columns []string
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				return nil, nil, errors.Errorf("nil row at index: %d", i)
+			}
+			elem = elem.Elem()
+		}
+
+		for _, key := range elem.MapKeys() {
+			if _, ok := seen[key.String()]; !ok {
+				seen[key.String()] = struct{}{}
+				columns = append(columns, key.String())
+			}
+		}
+		elems[i] = elem
+	}
+	sort.Strings(columns)
+
+	values := make([][]any, len(elems))
+	for i, elem := range elems {
+		row := make([]any, len(columns))
+		for j, column := range columns {
+			value := elem.MapIndex(reflect.ValueOf(column).Convert(elem.Type().Key()))
+			if value.IsValid() {
+				row[j] = value.Interface()
+			}
+		}
+		values[i] = row
+	}
+	return columns, values, nil
+}
+
+// getStructFields 通过db或者boil标签获取列名, 标签为-的字段忽略, 没有标签的字段忽略
+func getStructFields(t reflect.Type) []*structField {
+	if cached, ok := type2fields.Load(t); ok {
+		return cached.([]*structField)
+	}
+
+	var fields []*structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		column := getColumnName(sf)
+		if column == "-" {
+			continue
+		}
+
+		if column == "" {
+			// 没有标签的嵌入结构体展开
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				for _, f := range getStructFields(sf.Type) {
+					fields = append(fields, &structField{column: f.column, index: append([]int{i}, f.index...)})
+				}
+			}
+			continue
+		}
+
+		fields = append(fields, &structField{column: column, index: []int{i}})
+	}
+
+	type2fields.Store(t, fields)
+	return fields
+}
+
+func getColumnName(sf reflect.StructField) string {
+	for _, tag := range []string{"db", "boil"} {
+		if v, ok := sf.Tag.Lookup(tag); ok {
+			name, _, _ := strings.Cut(v, ",")
+			return name
+		}
+	}
+	return ""
+}
+
+// filterColumns 根据WithColumns和WithOmitColumns过滤列
+func filterColumns(columns []string, values [][]any, includes, excludes []string) ([]string, [][]any, error) {
+	if len(includes) == 0 && len(excludes) == 0 {
+		return columns, values, nil
+	}
+
+	column2index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		column2index[column] = i
+	}
+
+	selected := includes
+	if len(selected) == 0 {
+		selected = columns
+	}
+
+	omitted := make(map[string]struct{}, len(excludes))
+	for _, column := range excludes {
+		omitted[column] = struct{}{}
+	}
+
+	var (
+		keptColumns []string
+		keptIndexes []int
+	)
+	for _, column := range selected {
+		if _, ok := omitted[column]; ok {
+			continue
+		}
+
+		idx, ok := column2index[column]
+		if !ok {
+			return nil, nil, errors.Errorf("column not found: %s", column)
+		}
+		keptColumns = append(keptColumns, column)
+		keptIndexes = append(keptIndexes, idx)
+	}
+
+	keptValues := make([][]any, len(values))
+	for i, row := range values {
+		kept := make([]any, len(keptIndexes))
+		for j, idx := range keptIndexes {
+			kept[j] = row[idx]
+		}
+		keptValues[i] = kept
+	}
+	return keptColumns, keptValues, nil
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"github.com/hdget/hdutils/logger"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/singleflight"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Loader 缓存未命中时加载数据, 数据不存在时应返回ErrNotFound或sql.ErrNoRows
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// Cache 基于redis的cache-aside缓存
+type Cache[T any] struct {
+	client intf.RedisClient
+	option *cacheOption
+	group  singleflight.Group
+}
+
+var (
+	ErrNotFound = errors.New("not found")
+)
+
+const (
+	// luaAddTag 将key加入tag集合, 并保证tag集合的过期时间不短于key的过期时间
+	luaAddTag = `
+redis.call('SADD', KEYS[1], ARGV[1])
+local ttl = redis.call('TTL', KEYS[1])
+if ttl < tonumber(ARGV[2]) then
+	redis.call('EXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`
+)
+
+var (
+	scriptAddTag = redis.NewScript(1, luaAddTag)
+)
+
+func New[T any](client intf.RedisClient, options ...Option) *Cache[T] {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &Cache[T]{
+		client: client,
+		option: o,
+	}
+}
+
+// GetOrLoad 先从缓存获取, 未命中时调用loader加载并缓存, 同一个key的并发加载只会执行一次loader
+// 1. loader返回ErrNotFound或sql.ErrNoRows时, 会缓存未找到的结果negativeTtl时间, 并返回ErrNotFound
+// 2. 启用staleTtl时, 缓存过期后的staleTtl时间内直接返回旧值, 同时在后台刷新
+// 3. 可以指定tags, 之后通过InvalidateTags使这些tag关联的所有key失效
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader Loader[T], tags ...string) (T, error) {
+	e, err := c.get(key)
+	if err != nil {
+		// 缓存不可用时直接降级到loader
+		logger.Error("get cache", "key", key, "err", err)
+	}
+
+	if e != nil {
+		if !e.isStale() {
+			return c.value(e)
+		}
+
+		if c.option.staleTtl > 0 {
+			c.refresh(ctx, key, ttl, loader, tags)
+			return c.value(e)
+		}
+	}
+
+	// 同一个key的调用方共享一次加载, loader不能因为第一个调用方的ctx取消而让其他调用方都失败
+	loadCtx := context.WithoutCancel(ctx)
+	ch := c.group.DoChan(key, func() (any, error) {
+		return c.load(loadCtx, key, ttl, loader, tags)
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-ch:
+		if r.Err != nil {
+			return zero, r.Err
+		}
+		// T为接口类型时loader可能返回nil
+		v, _ := r.Val.(T)
+		return v, nil
+	}
+}
+
+// Invalidate 使指定的key失效
+func (c *Cache[T]) Invalidate(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Dels(keys)
+}
+
+// InvalidateTags 使tag关联的所有key失效
+func (c *Cache[T]) InvalidateTags(tags ...string) error {
+	for _, tag := range tags {
+		tagKey := c.getTagKey(tag)
+		keys, err := c.client.SMembers(tagKey)
+		if err != nil {
+			return errors.Wrapf(err, "get tag keys, tag: %s", tag)
+		}
+
+		err = c.client.Dels(append(keys, tagKey))
+		if err != nil {
+			return errors.Wrapf(err, "delete tag keys, tag: %s", tag)
+		}
+	}
+	return nil
+}
+
+func (c *Cache[T]) get(key string) (*entry, error) {
+	data, err := c.client.Get(key)
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return unmarshalEntry(data)
+}
+
+func (c *Cache[T]) value(e *entry) (T, error) {
+	if e.notFound {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return redis.Decode[T](c.option.codec, e.payload)
+}
+
+func (c *Cache[T]) load(ctx context.Context, key string, ttl time.Duration, loader Loader[T], tags []string) (T, error) {
+	v, err := loader(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) && !errors.Is(err, sql.ErrNoRows) {
+			return v, err
+		}
+
+		if c.option.negativeTtl > 0 {
+			err = c.set(key, &entry{notFound: true, softExpireAt: time.Now().Add(c.option.negativeTtl)}, c.option.negativeTtl, tags)
+			if err != nil {
+				logger.Error("set negative cache", "key", key, "err", err)
+			}
+		}
+		return v, ErrNotFound
+	}
+
+	payload, err := c.option.codec.Marshal(v)
+	if err != nil {
+		return v, errors.Wrap(err, "encode cache value")
+	}
+
+	ttl = c.jitter(ttl)
+	err = c.set(key, &entry{payload: payload, softExpireAt: time.Now().Add(ttl)}, ttl+c.option.staleTtl, tags)
+	if err != nil {
+		logger.Error("set cache", "key", key, "err", err)
+	}
+	return v, nil
+}
+
+// refresh 在后台刷新缓存, 与GetOrLoad共享singleflight, 同一个key同时只会有一个刷新
+func (c *Cache[T]) refresh(ctx context.Context, key string, ttl time.Duration, loader Loader[T], tags []string) {
+	bgCtx := context.WithoutCancel(ctx)
+	c.group.DoChan(key, func() (any, error) {
+		v, err := c.load(bgCtx, key, ttl, loader, tags)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			logger.Error("refresh cache", "key", key, "err", err)
+		}
+		return v, err
+	})
+}
+
+func (c *Cache[T]) set(key string, e *entry, ttl time.Duration, tags []string) error {
+	expire := toSeconds(ttl)
+	err := c.client.SetEx(key, e.marshal(), expire)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		_, err = scriptAddTag.Run(c.client, []any{c.getTagKey(tag)}, key, expire)
+		if err != nil {
+			return errors.Wrapf(err, "add cache tag, tag: %s", tag)
+		}
+	}
+	return nil
+}
+
+// jitter 在ttl上增加[-jitter*ttl, jitter*ttl]的随机抖动
+func (c *Cache[T]) jitter(ttl time.Duration) time.Duration {
+	if c.option.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	delta := float64(ttl) * c.option.jitter * (2*rand.Float64() - 1)
+	return ttl + time.Duration(delta)
+}
+
+func (c *Cache[T]) getTagKey(tag string) string {
+	return c.option.tagPrefix + tag
+}
+
+func toSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// entry 缓存项的存储格式: 1字节标记 + 8字节软过期时间(unix毫秒) + 编码后的值
+type entry struct {
+	notFound     bool
+	softExpireAt time.Time
+	payload      []byte
+}
+
+const (
+	entryFlagValue    byte = 'v'
+	entryFlagNotFound byte = 'n'
+	entryHeaderSize        = 9
+)
+
+func (e *entry) marshal() string {
+	buf := make([]byte, entryHeaderSize+len(e.payload))
+	buf[0] = entryFlagValue
+	if e.notFound {
+		buf[0] = entryFlagNotFound
+	}
+	binary.BigEndian.PutUint64(buf[1:entryHeaderSize], uint64(e.softExpireAt.UnixMilli()))
+	copy(buf[entryHeaderSize:], e.payload)
+	return string(buf)
+}
+
+func unmarshalEntry(data []byte) (*entry, error) {
+	if len(data) < entryHeaderSize || (data[0] != entryFlagValue && data[0] != entryFlagNotFound) {
+		return nil, errors.New("invalid cache entry")
+	}
+
+	return &entry{
+		notFound:     data[0] == entryFlagNotFound,
+		softExpireAt: time.UnixMilli(int64(binary.BigEndian.Uint64(data[1:entryHeaderSize]))),
+		payload:      data[entryHeaderSize:],
+	}, nil
+}
+
+func (e *entry) isStale() bool {
+	return time.Now().After(e.softExpireAt)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"time"
+)
+
+type Option func(*cacheOption)
+
+type cacheOption struct {
+	codec       redis.Codec
+	jitter      float64       // ttl的随机抖动比例, 避免大量key同时过期
+	negativeTtl time.Duration // 未找到结果的缓存时间, 为0表示不缓存
+	staleTtl    time.Duration // 过期后仍可返回旧值的时间, 期间会在后台刷新, 为0表示不启用
+	tagPrefix   string
+}
+
+const (
+	defaultJitter      = 0.1
+	defaultNegativeTtl = 30 * time.Second
+	defaultTagPrefix   = "cache:tag:"
+)
+
+func newOption() *cacheOption {
+	return &cacheOption{
+		codec:       redis.JSONCodec,
+		jitter:      defaultJitter,
+		negativeTtl: defaultNegativeTtl,
+		tagPrefix:   defaultTagPrefix,
+	}
+}
+
+func WithCodec(codec redis.Codec) Option {
+	return func(o *cacheOption) {
+		o.codec = codec
+	}
+}
+
+// WithJitter ttl的抖动比例, 取值范围[0, 1)
+func WithJitter(jitter float64) Option {
+	return func(o *cacheOption) {
+		if jitter >= 0 && jitter < 1 {
+			o.jitter = jitter
+		}
+	}
+}
+
+// WithNegativeTtl 未找到结果的缓存时间, 为0表示不缓存未找到的结果
+func WithNegativeTtl(ttl time.Duration) Option {
+	return func(o *cacheOption) {
+		o.negativeTtl = ttl
+	}
+}
+
+// WithStaleTtl 过期后仍可返回旧值的时间, 期间返回旧值并在后台刷新
+func WithStaleTtl(ttl time.Duration) Option {
+	return func(o *cacheOption) {
+		o.staleTtl = ttl
+	}
+}
+
+func WithTagPrefix(prefix string) Option {
+	return func(o *cacheOption) {
+		o.tagPrefix = prefix
+	}
+}
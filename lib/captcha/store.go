@@ -3,6 +3,7 @@ package captcha
 import (
 	"fmt"
 	"github.com/hdget/hdsdk/v2"
+	"github.com/hdget/hdsdk/v2/lib/redis"
 	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 )
@@ -26,6 +27,10 @@ end;
 `
 )
 
+var (
+	scriptCaptchaIncreaseFailures = redis.NewScript(1, luaCaptchaIncreaseFailures)
+)
+
 func Store(args ...string) CaptchaStore {
 	var generator string
 	if len(args) > 0 {
@@ -101,7 +106,7 @@ func (r redisCaptchaStore) getStoreValue(captchaValue string) string {
 }
 
 func (r redisCaptchaStore) increaseFailures(captchaId string) error {
-	_, err := hdsdk.Redis().My().Eval(luaCaptchaIncreaseFailures, []any{r.getStoreKey(captchaId)}, []any{maxFailures})
+	_, err := scriptCaptchaIncreaseFailures.Run(hdsdk.Redis().My(), []any{r.getStoreKey(captchaId)}, maxFailures)
 	if err != nil {
 		return errors.Wrap(err, "store increase captcha")
 	}
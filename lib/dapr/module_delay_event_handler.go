@@ -11,7 +11,8 @@ import (
 
 type delayEventHandler interface {
 	GetTopic() string
-	Handle(ctx context.Context, logger intf.LoggerProvider, msgChan <-chan *mq.Message)
+	// queueRetry为true时由延迟队列负责重试的时间和次数, 处理失败时直接Nack
+	Handle(ctx context.Context, logger intf.LoggerProvider, msgChan <-chan *mq.Message, queueRetry bool)
 }
 
 type delayEventHandlerImpl struct {
@@ -31,7 +32,7 @@ func (h delayEventHandlerImpl) GetTopic() string {
 // err: nil 只要错误为空，则消息成功消费, 不管retry的值为什么样
 // err: not nil + retry: false 打印DROP status消息
 // err: not nil + retry: true  进行重试，最后重试次数结束, 打印日志
-func (h delayEventHandlerImpl) Handle(ctx context.Context, logger intf.LoggerProvider, msgChan <-chan *mq.Message) {
+func (h delayEventHandlerImpl) Handle(ctx context.Context, logger intf.LoggerProvider, msgChan <-chan *mq.Message, queueRetry bool) {
 	// 挂载defer函数
 	defer func() {
 		if r := recover(); r != nil {
@@ -53,6 +54,9 @@ LOOP:
 				if !retry { // err != nil && retry == false
 					logger.Error("drop delay event", "err", err, "data", truncate(msg.Payload))
 					msg.Ack()
+				} else if queueRetry { // 不能在这里sleep, 否则会阻塞同一主题的所有消息
+					logger.Error("retry delay event", "err", err, "data", truncate(msg.Payload))
+					msg.Nack()
 				} else { // err != nil && retry == true
 					nextBackOff := h.module.GetBackOffPolicy().NextBackOff()
 					if nextBackOff == backoff.Stop {
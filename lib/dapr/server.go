@@ -8,6 +8,7 @@ import (
 	"github.com/dapr/go-sdk/service/http"
 	"github.com/hdget/hdsdk/v2"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/delayqueue"
 	"github.com/hdget/hdsdk/v2/provider/mq"
 	"github.com/pkg/errors"
 	"net"
@@ -170,12 +171,7 @@ func (impl *serverImpl) SubscribeDelayEvents() error {
 		return errors.New("app not found")
 	}
 
-	// if we configured delay event module, but no message queue configured raise error
-	if hdsdk.Mq() == nil {
-		return errors.New("sdk message queue not initialized")
-	}
-
-	delaySubscriber, err := hdsdk.Mq().NewSubscriber(app, &mq.SubscriberOption{SubscribeDelayMessage: true})
+	delaySubscriber, queueRetry, err := newDelaySubscriber(app)
 	if err != nil {
 		return errors.Wrapf(err, "new delaySubscriber, name: %s", app)
 	}
@@ -187,11 +183,27 @@ func (impl *serverImpl) SubscribeDelayEvents() error {
 		}
 
 		impl.logger.Debug("subscribe delay event", "topic", h.GetTopic())
-		go h.Handle(impl.ctx, impl.logger, msgChan)
+		go h.Handle(impl.ctx, impl.logger, msgChan, queueRetry)
 	}
 	return nil
 }
 
+// newDelaySubscriber 优先使用消息队列的延迟消息, 没有配置消息队列时使用redis延迟队列
+// redis延迟队列自己按退避时间重新投递并限制重试次数, 此时返回的queueRetry为true
+func newDelaySubscriber(app string) (intf.MessageQueueSubscriber, bool, error) {
+	if hdsdk.Mq() != nil {
+		subscriber, err := hdsdk.Mq().NewSubscriber(app, &mq.SubscriberOption{SubscribeDelayMessage: true})
+		return subscriber, false, err
+	}
+
+	if hdsdk.Redis() != nil {
+		return delayqueue.NewSubscriber(hdsdk.Redis().My()), true, nil
+	}
+
+	// if we configured delay event module, but neither message queue nor redis configured raise error
+	return nil, false, errors.New("sdk message queue or redis not initialized")
+}
+
 func (impl *serverImpl) GetHealthCheckHandler() common.HealthCheckHandler {
 	if len(_moduleName2healthModule) == 0 {
 		return emptyHealthCheckFunction
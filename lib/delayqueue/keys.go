@@ -0,0 +1,34 @@
+package delayqueue
+
+import "fmt"
+
+// topicKeys 同一个topic的所有key使用相同的hash tag, 保证在redis cluster中落在同一个slot
+type topicKeys struct {
+	delayed    string // 延迟集合, score为到期时间
+	ready      string // 就绪队列
+	processing string // 处理中集合, score为可见性超时的截止时间
+	payloads   string // 消息id=>消息内容
+	attempts   string // 消息id=>投递次数
+	dead       string // 死信队列
+}
+
+func newTopicKeys(prefix, topic string) *topicKeys {
+	base := fmt.Sprintf("%s{%s}", prefix, topic)
+	return &topicKeys{
+		delayed:    base + ":delayed",
+		ready:      base + ":ready",
+		processing: base + ":processing",
+		payloads:   base + ":payloads",
+		attempts:   base + ":attempts",
+		dead:       base + ":dead",
+	}
+}
+
+// DeadLetterKey 获取topic对应的死信队列key, 死信队列是一个list, 保存超过最大重试次数的消息内容
+func DeadLetterKey(topic string, options ...Option) string {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+	return newTopicKeys(o.keyPrefix, topic).dead
+}
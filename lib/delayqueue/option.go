@@ -0,0 +1,89 @@
+package delayqueue
+
+import "time"
+
+type Option func(*queueOption)
+
+type queueOption struct {
+	keyPrefix         string        // redis key前缀
+	visibilityTimeout time.Duration // 消息投递后多久未确认则重新投递
+	maxRetries        int64         // 最大重试次数, 超过后进入死信队列
+	backoffBase       time.Duration // 重试初始等待时间, 之后按2的指数增长
+	backoffMax        time.Duration // 重试最长等待时间
+	pollInterval      time.Duration // 没有到期消息时的轮询间隔
+	batchSize         int64         // 每次从延迟集合移动到就绪队列的最大消息数
+}
+
+const (
+	defaultKeyPrefix         = "delayqueue:"
+	defaultVisibilityTimeout = 30 * time.Second
+	defaultMaxRetries        = 3
+	defaultBackoffBase       = 3 * time.Second
+	defaultBackoffMax        = 5 * time.Minute
+	defaultPollInterval      = time.Second
+	defaultBatchSize         = 100
+)
+
+func newOption() *queueOption {
+	return &queueOption{
+		keyPrefix:         defaultKeyPrefix,
+		visibilityTimeout: defaultVisibilityTimeout,
+		maxRetries:        defaultMaxRetries,
+		backoffBase:       defaultBackoffBase,
+		backoffMax:        defaultBackoffMax,
+		pollInterval:      defaultPollInterval,
+		batchSize:         defaultBatchSize,
+	}
+}
+
+func WithKeyPrefix(prefix string) Option {
+	return func(o *queueOption) {
+		o.keyPrefix = prefix
+	}
+}
+
+// WithVisibilityTimeout 设置消息投递后未Ack/Nack的超时时间, 超时后消息会被重新投递
+func WithVisibilityTimeout(timeout time.Duration) Option {
+	return func(o *queueOption) {
+		if timeout > 0 {
+			o.visibilityTimeout = timeout
+		}
+	}
+}
+
+// WithMaxRetries 设置最大重试次数, 为0时失败的消息直接进入死信队列
+func WithMaxRetries(maxRetries int64) Option {
+	return func(o *queueOption) {
+		if maxRetries >= 0 {
+			o.maxRetries = maxRetries
+		}
+	}
+}
+
+// WithBackoff 设置重试的等待时间, 第n次重试等待base*2^(n-1), 最长不超过max
+func WithBackoff(base, max time.Duration) Option {
+	return func(o *queueOption) {
+		if base > 0 {
+			o.backoffBase = base
+		}
+		if max >= base {
+			o.backoffMax = max
+		}
+	}
+}
+
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *queueOption) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+func WithBatchSize(batchSize int64) Option {
+	return func(o *queueOption) {
+		if batchSize > 0 {
+			o.batchSize = batchSize
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package delayqueue
+
+import (
+	"github.com/google/uuid"
+	"github.com/hdget/hdsdk/v2/intf"
+	"time"
+)
+
+type publisherImpl struct {
+	client intf.RedisClient
+	option *queueOption
+}
+
+var (
+	_ intf.MessageQueuePublisher = (*publisherImpl)(nil)
+)
+
+// NewPublisher 创建基于redis的延迟消息发布者
+func NewPublisher(client intf.RedisClient, options ...Option) intf.MessageQueuePublisher {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &publisherImpl{
+		client: client,
+		option: o,
+	}
+}
+
+// Publish 发布消息, delaySeconds为延迟时间, 不指定则消息立即到期
+func (p *publisherImpl) Publish(topic string, messages [][]byte, delaySeconds ...int64) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var delay time.Duration
+	if len(delaySeconds) > 0 && delaySeconds[0] > 0 {
+		delay = time.Duration(delaySeconds[0]) * time.Second
+	}
+
+	args := []any{time.Now().Add(delay).UnixMilli()}
+	for _, message := range messages {
+		args = append(args, uuid.NewString(), message)
+	}
+
+	keys := newTopicKeys(p.option.keyPrefix, topic)
+	_, err := scriptPublish.Run(p.client, []any{keys.payloads, keys.delayed}, args...)
+	return err
+}
+
+func (p *publisherImpl) Close() error {
+	return nil
+}
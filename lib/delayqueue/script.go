@@ -0,0 +1,104 @@
+package delayqueue
+
+import "github.com/hdget/hdsdk/v2/lib/redis"
+
+const (
+	// KEYS[1]: 消息内容hash, KEYS[2]: 延迟集合
+	// ARGV[1]: 到期时间(毫秒), ARGV[2..n]: 消息id和内容交替
+	luaPublish = `
+for i = 2, #ARGV, 2 do
+	redis.call('HSET', KEYS[1], ARGV[i], ARGV[i+1])
+	redis.call('ZADD', KEYS[2], ARGV[1], ARGV[i])
+end
+return (#ARGV - 1) / 2
+`
+
+	// 将到期的延迟消息和可见性超时的消息移动到就绪队列, 超时且超过最大重试次数的消息进入死信队列
+	// KEYS[1]: 延迟集合, KEYS[2]: 就绪队列, KEYS[3]: 处理中集合, KEYS[4]: 消息内容hash, KEYS[5]: 重试次数hash, KEYS[6]: 死信队列
+	// ARGV[1]: 当前时间(毫秒), ARGV[2]: 批量大小, ARGV[3]: 最大重试次数
+	luaMoveToReady = `
+local moved = 0
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('RPUSH', KEYS[2], id)
+	moved = moved + 1
+end
+local expired = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(expired) do
+	redis.call('ZREM', KEYS[3], id)
+	local attempts = tonumber(redis.call('HGET', KEYS[5], id) or '0')
+	if attempts > tonumber(ARGV[3]) then
+		local payload = redis.call('HGET', KEYS[4], id)
+		if payload then
+			redis.call('RPUSH', KEYS[6], payload)
+		end
+		redis.call('HDEL', KEYS[4], id)
+		redis.call('HDEL', KEYS[5], id)
+	else
+		redis.call('RPUSH', KEYS[2], id)
+		moved = moved + 1
+	end
+end
+return moved
+`
+
+	// 从就绪队列取出一条消息并放入处理中集合
+	// KEYS[1]: 就绪队列, KEYS[2]: 处理中集合, KEYS[3]: 消息内容hash, KEYS[4]: 重试次数hash
+	// ARGV[1]: 可见性超时的截止时间(毫秒)
+	luaReserve = `
+while true do
+	local id = redis.call('LPOP', KEYS[1])
+	if not id then
+		return false
+	end
+	local payload = redis.call('HGET', KEYS[3], id)
+	if payload then
+		redis.call('ZADD', KEYS[2], ARGV[1], id)
+		local attempts = redis.call('HINCRBY', KEYS[4], id, 1)
+		return {id, payload, attempts}
+	end
+	redis.call('HDEL', KEYS[4], id)
+end
+`
+
+	// KEYS[1]: 处理中集合, KEYS[2]: 消息内容hash, KEYS[3]: 重试次数hash
+	// ARGV[1]: 消息id
+	luaAck = `
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1
+`
+
+	// 消息处理失败, 未超过最大重试次数则重新放入延迟集合, 否则进入死信队列
+	// KEYS[1]: 处理中集合, KEYS[2]: 延迟集合, KEYS[3]: 消息内容hash, KEYS[4]: 重试次数hash, KEYS[5]: 死信队列
+	// ARGV[1]: 消息id, ARGV[2]: 重试时间(毫秒), ARGV[3]: 最大重试次数
+	luaNack = `
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+local attempts = tonumber(redis.call('HGET', KEYS[4], ARGV[1]) or '0')
+if attempts > tonumber(ARGV[3]) then
+	local payload = redis.call('HGET', KEYS[3], ARGV[1])
+	if payload then
+		redis.call('RPUSH', KEYS[5], payload)
+	end
+	redis.call('HDEL', KEYS[3], ARGV[1])
+	redis.call('HDEL', KEYS[4], ARGV[1])
+	return 2
+end
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+return 1
+`
+)
+
+var (
+	scriptPublish     = redis.NewScript(2, luaPublish)
+	scriptMoveToReady = redis.NewScript(6, luaMoveToReady)
+	scriptReserve     = redis.NewScript(4, luaReserve)
+	scriptAck         = redis.NewScript(3, luaAck)
+	scriptNack        = redis.NewScript(5, luaNack)
+)
@@ -0,0 +1,198 @@
+package delayqueue
+
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"github.com/hdget/hdsdk/v2/provider/mq"
+	"github.com/hdget/hdutils/logger"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+type subscriberImpl struct {
+	client     intf.RedisClient
+	option     *queueOption
+	closeOnce  sync.Once
+	closedChan chan struct{}
+	waitGroup  sync.WaitGroup
+}
+
+type job struct {
+	id       string
+	payload  []byte
+	attempts int64
+	deadline time.Time // 可见性超时时间, 超时后消息会被重新投递
+}
+
+var (
+	_ intf.MessageQueueSubscriber = (*subscriberImpl)(nil)
+)
+
+// NewSubscriber 创建基于redis的延迟消息订阅者
+//
+// 消息投递后需要在可见性超时时间内调用Ack或者Nack, 否则会被重新投递
+// Nack的消息按照指数退避重新进入延迟集合, 超过最大重试次数后进入死信队列
+func NewSubscriber(client intf.RedisClient, options ...Option) intf.MessageQueueSubscriber {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &subscriberImpl{
+		client:     client,
+		option:     o,
+		closedChan: make(chan struct{}),
+	}
+}
+
+// Subscribe 订阅topic, ctx取消或者Close后输出channel会被关闭
+func (s *subscriberImpl) Subscribe(ctx context.Context, topic string) (<-chan *mq.Message, error) {
+	select {
+	case <-s.closedChan:
+		return nil, errors.New("subscriber is closed")
+	default:
+	}
+
+	out := make(chan *mq.Message)
+	keys := newTopicKeys(s.option.keyPrefix, topic)
+
+	s.waitGroup.Add(1)
+	go func() {
+		defer func() {
+			close(out)
+			s.waitGroup.Done()
+		}()
+
+		for {
+			j, err := s.next(keys)
+			if err != nil {
+				logger.Error("fetch delay message", "topic", topic, "err", err)
+			}
+
+			if j == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.closedChan:
+					return
+				case <-time.After(s.option.pollInterval):
+					continue
+				}
+			}
+
+			if !s.deliver(ctx, keys, j, out) {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (s *subscriberImpl) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closedChan)
+	})
+	s.waitGroup.Wait()
+	return nil
+}
+
+// next 先将到期的消息移动到就绪队列, 然后取出一条消息
+func (s *subscriberImpl) next(keys *topicKeys) (*job, error) {
+	now := time.Now()
+	deadline := now.Add(s.option.visibilityTimeout)
+	_, err := scriptMoveToReady.Run(s.client,
+		[]any{keys.delayed, keys.ready, keys.processing, keys.payloads, keys.attempts, keys.dead},
+		now.UnixMilli(), s.option.batchSize, s.option.maxRetries,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "move to ready")
+	}
+
+	values, err := redis.Values(scriptReserve.Run(s.client,
+		[]any{keys.ready, keys.processing, keys.payloads, keys.attempts},
+		deadline.UnixMilli(),
+	))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reserve")
+	}
+
+	if len(values) != 3 {
+		return nil, errors.Errorf("invalid reserve reply, len: %d", len(values))
+	}
+
+	id, err := redis.String(values[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := redis.Bytes(values[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := redis.Int64(values[2], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job{id: id, payload: payload, attempts: attempts, deadline: deadline}, nil
+}
+
+// deliver 投递消息并等待确认, 返回false表示订阅已经结束
+// 订阅结束时未确认的消息保留在处理中集合, 可见性超时后会被重新投递
+// 可见性超时后不再等待确认, 消息的ctx被取消, 之后的Ack或Nack被忽略, 继续拉取下一条消息
+func (s *subscriberImpl) deliver(ctx context.Context, keys *topicKeys, j *job, out chan *mq.Message) bool {
+	msg := mq.NewMessage(j.payload)
+	msgCtx, cancel := context.WithCancel(ctx)
+	msg.SetContext(msgCtx)
+	defer cancel()
+
+	timer := time.NewTimer(time.Until(j.deadline))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.closedChan:
+		return false
+	case <-timer.C:
+		return true
+	case out <- msg:
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.closedChan:
+		return false
+	case <-timer.C:
+		logger.Warn("delay message visibility timeout, give up waiting for confirmation", "id", j.id)
+		return true
+	case <-msg.Acked():
+		_, err = scriptAck.Run(s.client, []any{keys.processing, keys.payloads, keys.attempts}, j.id)
+	case <-msg.Nacked():
+		retryAt := time.Now().Add(s.getBackoff(j.attempts)).UnixMilli()
+		_, err = scriptNack.Run(s.client,
+			[]any{keys.processing, keys.delayed, keys.payloads, keys.attempts, keys.dead},
+			j.id, retryAt, s.option.maxRetries,
+		)
+	}
+	if err != nil {
+		logger.Error("confirm delay message", "id", j.id, "err", err)
+	}
+	return true
+}
+
+// getBackoff 第n次投递失败后的等待时间
+func (s *subscriberImpl) getBackoff(attempts int64) time.Duration {
+	backoff := s.option.backoffBase
+	for i := int64(1); i < attempts && backoff < s.option.backoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.option.backoffMax)
+}
@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/hdget/hdsdk/v2/lib/sqltemplate"
+	"github.com/hdget/hdsdk/v2/protobuf"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+func (t *translatorImpl) Template(tpl sqltemplate.SqlTemplate, group *protobuf.FilterGroup, sorts []*protobuf.SortParam) error {
+	where, args, orderBy, err := t.translate(group, sorts)
+	if err != nil {
+		return err
+	}
+
+	if where != "" {
+		tpl.Where(where, args...)
+	}
+
+	if orderBy != "" {
+		tpl.OrderBy(orderBy)
+	}
+	return nil
+}
+
+func (t *translatorImpl) SelectBuilder(bd squirrel.SelectBuilder, group *protobuf.FilterGroup, sorts []*protobuf.SortParam) (squirrel.SelectBuilder, error) {
+	where, args, orderBy, err := t.translate(group, sorts)
+	if err != nil {
+		return bd, err
+	}
+
+	if where != "" {
+		bd = bd.Where(where, args...)
+	}
+
+	if orderBy != "" {
+		bd = bd.OrderBy(orderBy)
+	}
+	return bd, nil
+}
+
+func (t *translatorImpl) QueryMods(group *protobuf.FilterGroup, sorts []*protobuf.SortParam) ([]qm.QueryMod, error) {
+	where, args, orderBy, err := t.translate(group, sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []qm.QueryMod
+	if where != "" {
+		mods = append(mods, qm.Where(where, args...))
+	}
+
+	if orderBy != "" {
+		mods = append(mods, qm.OrderBy(orderBy))
+	}
+	return mods, nil
+}
+
+func (t *translatorImpl) translate(group *protobuf.FilterGroup, sorts []*protobuf.SortParam) (string, []any, string, error) {
+	where, args, err := t.Where(group)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	orderBy, err := t.OrderBy(sorts)
+	if err != nil {
+		return "", nil, "", err
+	}
+	return where, args, orderBy, nil
+}
@@ -0,0 +1,98 @@
+package filter
+
+import (
+	"github.com/hdget/hdsdk/v2/protobuf"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType 字段类型, 客户端传入的值按类型转换, 转换失败时报错
+type FieldType int
+
+const (
+	FieldTypeString FieldType = iota
+	FieldTypeInt
+	FieldTypeFloat
+	FieldTypeBool
+	FieldTypeTime
+)
+
+// Field 允许客户端过滤或排序的字段
+type Field struct {
+	Column    string                    // 数据库列名, 可以带表别名, e,g: u.created_at
+	Type      FieldType                 // 字段类型
+	Operators []protobuf.FilterOperator // 允许的操作符, 为空时使用字段类型的缺省操作符, Like只能用于字符串字段
+	Sortable  bool                      // 是否允许排序
+}
+
+// Whitelist 每个接口的字段白名单, key为客户端传入的字段名
+type Whitelist map[string]*Field
+
+var (
+	comparableOperators = []protobuf.FilterOperator{
+		protobuf.FilterOperator_Eq, protobuf.FilterOperator_Ne,
+		protobuf.FilterOperator_Gt, protobuf.FilterOperator_Gte, protobuf.FilterOperator_Lt, protobuf.FilterOperator_Lte,
+		protobuf.FilterOperator_In, protobuf.FilterOperator_NotIn, protobuf.FilterOperator_Between,
+		protobuf.FilterOperator_IsNull, protobuf.FilterOperator_IsNotNull,
+	}
+
+	type2defaultOperators = map[FieldType][]protobuf.FilterOperator{
+		FieldTypeString: {
+			protobuf.FilterOperator_Eq, protobuf.FilterOperator_Ne,
+			protobuf.FilterOperator_In, protobuf.FilterOperator_NotIn, protobuf.FilterOperator_Like,
+			protobuf.FilterOperator_IsNull, protobuf.FilterOperator_IsNotNull,
+		},
+		FieldTypeInt:   comparableOperators,
+		FieldTypeFloat: comparableOperators,
+		FieldTypeTime:  comparableOperators,
+		FieldTypeBool: {
+			protobuf.FilterOperator_Eq, protobuf.FilterOperator_Ne,
+			protobuf.FilterOperator_IsNull, protobuf.FilterOperator_IsNotNull,
+		},
+	}
+
+	// 支持的时间格式, 没有时区的按本地时间解析
+	timeLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly}
+)
+
+// allow Like只能用于字符串字段, 即使在Operators中指定也不允许用于其他类型
+func (f *Field) allow(op protobuf.FilterOperator) bool {
+	if op == protobuf.FilterOperator_Like && f.Type != FieldTypeString {
+		return false
+	}
+
+	operators := f.Operators
+	if len(operators) == 0 {
+		operators = type2defaultOperators[f.Type]
+	}
+
+	for _, allowed := range operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// convert 将客户端传入的字符串转换为字段类型对应的值
+func (f *Field) convert(value string) (any, error) {
+	switch f.Type {
+	case FieldTypeInt:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case FieldTypeFloat:
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case FieldTypeBool:
+		return strconv.ParseBool(strings.TrimSpace(value))
+	case FieldTypeTime:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return nil, errors.Errorf("invalid time: %s", value)
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,228 @@
+package filter
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/hdget/hdsdk/v2/lib/sqltemplate"
+	"github.com/hdget/hdsdk/v2/protobuf"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strings"
+)
+
+// Translator 将客户端传入的过滤条件和排序字段转换为SQL, 只允许白名单中的字段和操作符, 值全部通过占位符传入
+type Translator interface {
+	// Where 生成?占位符的条件, 没有过滤条件时返回空
+	Where(group *protobuf.FilterGroup) (string, []any, error)
+	// OrderBy 生成不包含ORDER BY的排序语句, 没有排序字段时返回空
+	OrderBy(sorts []*protobuf.SortParam) (string, error)
+	// Template 将条件和排序加到sqltemplate中
+	Template(tpl sqltemplate.SqlTemplate, group *protobuf.FilterGroup, sorts []*protobuf.SortParam) error
+	// SelectBuilder 将条件和排序加到squirrel的SelectBuilder中
+	SelectBuilder(bd squirrel.SelectBuilder, group *protobuf.FilterGroup, sorts []*protobuf.SortParam) (squirrel.SelectBuilder, error)
+	// QueryMods 生成sqlboiler的QueryMods
+	QueryMods(group *protobuf.FilterGroup, sorts []*protobuf.SortParam) ([]qm.QueryMod, error)
+}
+
+type translatorImpl struct {
+	*translatorOption
+	whitelist Whitelist
+}
+
+var (
+	// ErrInvalidFilter 过滤条件或排序字段不合法, 可以通过errors.Is判断后返回参数错误
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+const (
+	// likeEscape LIKE的转义字符, 不使用反斜杠是因为SQLite没有缺省的转义字符且各数据库对反斜杠的处理不同
+	likeEscape = "!"
+)
+
+var (
+	likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+)
+
+func New(whitelist Whitelist, options ...Option) Translator {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &translatorImpl{
+		translatorOption: o,
+		whitelist:        whitelist,
+	}
+}
+
+func (t *translatorImpl) Where(group *protobuf.FilterGroup) (string, []any, error) {
+	if group == nil {
+		return "", nil, nil
+	}
+
+	var count int
+	return t.translateGroup(group, 1, &count)
+}
+
+func (t *translatorImpl) OrderBy(sorts []*protobuf.SortParam) (string, error) {
+	if len(sorts) > t.maxSorts {
+		return "", errors.Wrapf(ErrInvalidFilter, "too many sort fields, max: %d", t.maxSorts)
+	}
+
+	orders := make([]string, 0, len(sorts))
+	seen := make(map[string]struct{}, len(sorts))
+	for _, sort := range sorts {
+		field, exists := t.whitelist[sort.GetField()]
+		if !exists || !field.Sortable {
+			return "", errors.Wrapf(ErrInvalidFilter, "sort field not allowed: %s", sort.GetField())
+		}
+
+		if _, duplicated := seen[sort.GetField()]; duplicated {
+			return "", errors.Wrapf(ErrInvalidFilter, "duplicated sort field: %s", sort.GetField())
+		}
+		seen[sort.GetField()] = struct{}{}
+
+		switch sort.GetDirection() {
+		case protobuf.SortDirection_Desc:
+			orders = append(orders, field.Column+" DESC")
+		default:
+			orders = append(orders, field.Column+" ASC")
+		}
+	}
+	return strings.Join(orders, ", "), nil
+}
+
+// translateGroup 递归转换条件组, 空的条件组忽略
+func (t *translatorImpl) translateGroup(group *protobuf.FilterGroup, depth int, count *int) (string, []any, error) {
+	if depth > t.maxDepth {
+		return "", nil, errors.Wrapf(ErrInvalidFilter, "filter group too deep, max: %d", t.maxDepth)
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+	for _, f := range group.GetFilters() {
+		*count++
+		if *count > t.maxFilters {
+			return "", nil, errors.Wrapf(ErrInvalidFilter, "too many filters, max: %d", t.maxFilters)
+		}
+
+		condition, filterArgs, err := t.translateFilter(f)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+
+	for _, subGroup := range group.GetGroups() {
+		condition, groupArgs, err := t.translateGroup(subGroup, depth+1, count)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if condition != "" {
+			conditions = append(conditions, condition)
+			args = append(args, groupArgs...)
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		return conditions[0], args, nil
+	}
+
+	logic := " AND "
+	if group.GetLogic() == protobuf.FilterLogic_Or {
+		logic = " OR "
+	}
+	return "(" + strings.Join(conditions, logic) + ")", args, nil
+}
+
+func (t *translatorImpl) translateFilter(f *protobuf.Filter) (string, []any, error) {
+	field, exists := t.whitelist[f.GetField()]
+	if !exists {
+		return "", nil, errors.Wrapf(ErrInvalidFilter, "field not allowed: %s", f.GetField())
+	}
+
+	op := f.GetOperator()
+	if !field.allow(op) {
+		return "", nil, errors.Wrapf(ErrInvalidFilter, "operator not allowed, field: %s, operator: %s", f.GetField(), op)
+	}
+
+	values, err := t.convertValues(field, f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	column := field.Column
+	switch op {
+	case protobuf.FilterOperator_IsNull:
+		return column + " IS NULL", nil, nil
+	case protobuf.FilterOperator_IsNotNull:
+		return column + " IS NOT NULL", nil, nil
+	case protobuf.FilterOperator_In, protobuf.FilterOperator_NotIn:
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		if op == protobuf.FilterOperator_NotIn {
+			return fmt.Sprintf("%s NOT IN (%s)", column, placeholders), values, nil
+		}
+		return fmt.Sprintf("%s IN (%s)", column, placeholders), values, nil
+	case protobuf.FilterOperator_Between:
+		return column + " BETWEEN ? AND ?", values, nil
+	case protobuf.FilterOperator_Like:
+		// 按包含匹配, 客户端传入的通配符被转义
+		pattern := "%" + likeEscaper.Replace(f.GetValues()[0]) + "%"
+		return fmt.Sprintf("%s LIKE ? ESCAPE '%s'", column, likeEscape), []any{pattern}, nil
+	}
+
+	var sqlOp string
+	switch op {
+	case protobuf.FilterOperator_Eq:
+		sqlOp = "="
+	case protobuf.FilterOperator_Ne:
+		sqlOp = "<>"
+	case protobuf.FilterOperator_Gt:
+		sqlOp = ">"
+	case protobuf.FilterOperator_Gte:
+		sqlOp = ">="
+	case protobuf.FilterOperator_Lt:
+		sqlOp = "<"
+	case protobuf.FilterOperator_Lte:
+		sqlOp = "<="
+	default:
+		return "", nil, errors.Wrapf(ErrInvalidFilter, "unsupported operator: %s", op)
+	}
+	return fmt.Sprintf("%s %s ?", column, sqlOp), values, nil
+}
+
+// convertValues 检查值的数量并按字段类型转换
+func (t *translatorImpl) convertValues(field *Field, f *protobuf.Filter) ([]any, error) {
+	var valid bool
+	switch n := len(f.GetValues()); f.GetOperator() {
+	case protobuf.FilterOperator_IsNull, protobuf.FilterOperator_IsNotNull:
+		valid = n == 0
+	case protobuf.FilterOperator_In, protobuf.FilterOperator_NotIn:
+		valid = n > 0 && n <= t.maxValues
+	case protobuf.FilterOperator_Between:
+		valid = n == 2
+	default:
+		valid = n == 1
+	}
+
+	if !valid {
+		return nil, errors.Wrapf(ErrInvalidFilter, "invalid value count, field: %s, operator: %s", f.GetField(), f.GetOperator())
+	}
+
+	values := make([]any, len(f.GetValues()))
+	for i, s := range f.GetValues() {
+		v, err := field.convert(s)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidFilter, "invalid value, field: %s, value: %s", f.GetField(), s)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
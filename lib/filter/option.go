@@ -0,0 +1,50 @@
+package filter
+
+type Option func(*translatorOption)
+
+type translatorOption struct {
+	maxDepth   int // 条件组最多嵌套的层数
+	maxFilters int // 最多的过滤条件数量
+	maxValues  int // In/NotIn最多的值数量
+	maxSorts   int // 最多的排序字段数量
+}
+
+const (
+	defaultMaxDepth   = 3
+	defaultMaxFilters = 20
+	defaultMaxValues  = 100
+	defaultMaxSorts   = 3
+)
+
+func newOption() *translatorOption {
+	return &translatorOption{
+		maxDepth:   defaultMaxDepth,
+		maxFilters: defaultMaxFilters,
+		maxValues:  defaultMaxValues,
+		maxSorts:   defaultMaxSorts,
+	}
+}
+
+func WithMaxDepth(maxDepth int) Option {
+	return func(o *translatorOption) {
+		o.maxDepth = maxDepth
+	}
+}
+
+func WithMaxFilters(maxFilters int) Option {
+	return func(o *translatorOption) {
+		o.maxFilters = maxFilters
+	}
+}
+
+func WithMaxValues(maxValues int) Option {
+	return func(o *translatorOption) {
+		o.maxValues = maxValues
+	}
+}
+
+func WithMaxSorts(maxSorts int) Option {
+	return func(o *translatorOption) {
+		o.maxSorts = maxSorts
+	}
+}
@@ -0,0 +1,282 @@
+package leader
+
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"github.com/hdget/hdutils/logger"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Elector 基于redis租约的leader选举, 同一个name下同时只有一个节点能成为leader
+type Elector interface {
+	Campaign(ctx context.Context, name string) error // 在后台开始竞选, ctx取消或调用Resign时放弃leader
+	Resign(ctx context.Context) error                // 放弃leader并停止竞选, ctx超时返回时竞选循环退出前Campaign返回ErrStillResigning
+	IsLeader() bool                                  // 当前节点是否是leader
+	Token() int64                                    // 当前leader任期的fencing token, 每次选举成功单调递增, 非leader时为0
+	OnElected(fn ElectedFunc)                        // 成为leader时回调
+	OnRevoked(fn RevokedFunc)                        // 失去leader时回调
+}
+
+// ElectedFunc 成为leader时回调, ctx在失去leader时被取消, 适合在其中运行单例任务
+type ElectedFunc func(ctx context.Context, token int64)
+
+// RevokedFunc 失去leader时回调
+type RevokedFunc func()
+
+type electorImpl struct {
+	client intf.RedisClient
+	option *electorOption
+
+	mutex         sync.RWMutex
+	token         int64
+	cancelLeading context.CancelFunc // 取消leader任期内的ctx
+	stopCampaign  context.CancelFunc
+	done          chan struct{} // 竞选循环退出时关闭
+	onElected     []ElectedFunc
+	onRevoked     []RevokedFunc
+	shutdownOnce  sync.Once // 多次Campaign只注册一次sdk关闭时的Resign
+}
+
+// campaign 一次竞选的状态, 只在竞选循环的goroutine中访问, 不需要加锁
+type campaign struct {
+	name        string
+	lastRenewAt time.Time
+}
+
+const (
+	// KEYS[1]: 租约key, KEYS[2]: fencing token key, ARGV[1]: 节点标识, ARGV[2]: 租约时间(毫秒)
+	luaAcquire = `
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0
+`
+	// KEYS[1]: 租约key, ARGV[1]: 节点标识, ARGV[2]: 租约时间(毫秒)
+	luaRenew = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`
+	// KEYS[1]: 租约key, ARGV[1]: 节点标识
+	luaRelease = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+)
+
+var (
+	scriptAcquire = redis.NewScript(2, luaAcquire)
+	scriptRenew   = redis.NewScript(1, luaRenew)
+	scriptRelease = redis.NewScript(1, luaRelease)
+)
+
+var (
+	ErrAlreadyCampaigning = errors.New("already campaigning")
+	ErrStillResigning     = errors.New("previous campaign is still resigning")
+)
+
+// New 创建leader选举器, 如果sdk已经初始化, sdk关闭时会自动放弃leader
+//
+// e,g:
+//
+//	elector := leader.New(hdsdk.Redis().My())
+//	elector.OnElected(func(ctx context.Context, token int64) {
+//		runCleanupLoop(ctx)
+//	})
+//	err := elector.Campaign(context.Background(), "cleanup")
+func New(client intf.RedisClient, options ...Option) Elector {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &electorImpl{
+		client: client,
+		option: o,
+	}
+}
+
+func (e *electorImpl) Campaign(ctx context.Context, name string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stopCampaign != nil {
+		return ErrAlreadyCampaigning
+	}
+
+	// Resign超时返回时上一次的竞选循环可能还没有退出, 不能同时运行两个竞选循环
+	if e.done != nil {
+		select {
+		case <-e.done:
+		default:
+			return ErrStillResigning
+		}
+	}
+
+	campaignCtx, cancel := context.WithCancel(ctx)
+	e.stopCampaign = cancel
+	e.done = make(chan struct{})
+
+	if hdsdk.HasInitialized() {
+		e.shutdownOnce.Do(func() {
+			hdsdk.GetInstance().OnShutdown(e.Resign)
+		})
+	}
+
+	go e.run(campaignCtx, &campaign{name: name}, e.done)
+	return nil
+}
+
+func (e *electorImpl) Resign(ctx context.Context) error {
+	e.mutex.Lock()
+	stop, done := e.stopCampaign, e.done
+	e.stopCampaign = nil
+	e.mutex.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+
+	stop()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *electorImpl) IsLeader() bool {
+	return e.Token() > 0
+}
+
+func (e *electorImpl) Token() int64 {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.token
+}
+
+func (e *electorImpl) OnElected(fn ElectedFunc) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.onElected = append(e.onElected, fn)
+}
+
+func (e *electorImpl) OnRevoked(fn RevokedFunc) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.onRevoked = append(e.onRevoked, fn)
+}
+
+func (e *electorImpl) run(ctx context.Context, c *campaign, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(e.option.renewInterval)
+	defer ticker.Stop()
+
+	e.tick(c)
+	for {
+		select {
+		case <-ctx.Done():
+			e.release(c)
+			return
+		case <-ticker.C:
+			e.tick(c)
+		}
+	}
+}
+
+// tick leader续约, 非leader则尝试竞选
+func (e *electorImpl) tick(c *campaign) {
+	if !e.IsLeader() {
+		e.acquire(c)
+		return
+	}
+
+	renewed, err := redis.Int64(scriptRenew.Run(e.client, []any{e.getLeaseKey(c.name)}, e.option.nodeId, e.option.ttl.Milliseconds()))
+	if err != nil {
+		logger.Error("renew leader lease", "name", c.name, "err", err)
+		// 无法确认租约是否还有效, 超过租约时间后认为已经失去leader
+		if time.Since(c.lastRenewAt) < e.option.ttl {
+			return
+		}
+	}
+
+	if err != nil || renewed == 0 {
+		e.revoke(c)
+		return
+	}
+
+	c.lastRenewAt = time.Now()
+}
+
+func (e *electorImpl) acquire(c *campaign) {
+	token, err := redis.Int64(scriptAcquire.Run(e.client, []any{e.getLeaseKey(c.name), e.getTokenKey(c.name)}, e.option.nodeId, e.option.ttl.Milliseconds()))
+	if err != nil {
+		logger.Error("acquire leader lease", "name", c.name, "err", err)
+		return
+	}
+
+	if token > 0 {
+		c.lastRenewAt = time.Now()
+		e.elect(c, token)
+	}
+}
+
+func (e *electorImpl) release(c *campaign) {
+	if !e.IsLeader() {
+		return
+	}
+
+	_, err := scriptRelease.Run(e.client, []any{e.getLeaseKey(c.name)}, e.option.nodeId)
+	if err != nil {
+		logger.Error("release leader lease", "name", c.name, "err", err)
+	}
+	e.revoke(c)
+}
+
+func (e *electorImpl) elect(c *campaign, token int64) {
+	leadingCtx, cancel := context.WithCancel(context.Background())
+
+	e.mutex.Lock()
+	e.token = token
+	e.cancelLeading = cancel
+	callbacks := e.onElected
+	e.mutex.Unlock()
+
+	logger.Debug("elected as leader", "name", c.name, "node", e.option.nodeId, "token", token)
+	for _, fn := range callbacks {
+		go fn(leadingCtx, token)
+	}
+}
+
+func (e *electorImpl) revoke(c *campaign) {
+	e.mutex.Lock()
+	e.token = 0
+	if e.cancelLeading != nil {
+		e.cancelLeading()
+		e.cancelLeading = nil
+	}
+	callbacks := e.onRevoked
+	e.mutex.Unlock()
+
+	logger.Debug("revoked from leader", "name", c.name, "node", e.option.nodeId)
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+func (e *electorImpl) getLeaseKey(name string) string {
+	return e.option.keyPrefix + name
+}
+
+func (e *electorImpl) getTokenKey(name string) string {
+	return e.option.keyPrefix + name + ":token"
+}
@@ -0,0 +1,54 @@
+package leader
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"time"
+)
+
+type Option func(*electorOption)
+
+type electorOption struct {
+	nodeId        string        // 节点标识
+	ttl           time.Duration // 租约时间
+	renewInterval time.Duration // 续约/竞选间隔
+	keyPrefix     string
+}
+
+const (
+	defaultTtl       = 15 * time.Second
+	defaultKeyPrefix = "leader:"
+)
+
+func newOption() *electorOption {
+	hostname, _ := os.Hostname()
+	return &electorOption{
+		nodeId:        fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.NewString()),
+		ttl:           defaultTtl,
+		renewInterval: defaultTtl / 3,
+		keyPrefix:     defaultKeyPrefix,
+	}
+}
+
+func WithNodeId(nodeId string) Option {
+	return func(o *electorOption) {
+		o.nodeId = nodeId
+	}
+}
+
+// WithTtl 设置租约时间, 续约间隔为租约时间的1/3
+func WithTtl(ttl time.Duration) Option {
+	return func(o *electorOption) {
+		if ttl > 0 {
+			o.ttl = ttl
+			o.renewInterval = ttl / 3
+		}
+	}
+}
+
+func WithKeyPrefix(prefix string) Option {
+	return func(o *electorOption) {
+		o.keyPrefix = prefix
+	}
+}
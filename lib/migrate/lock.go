@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/pkg/errors"
+	"os"
+	"time"
+)
+
+const (
+	lockId = 1
+	// lockHeartbeatRatio 每lockTtl内刷新锁的次数
+	lockHeartbeatRatio = 3
+)
+
+// withLock 获取迁移锁后执行fn, 保证多个副本同时启动时只有一个执行迁移
+// 锁通过向锁表插入固定主键的记录实现, 不依赖数据库特有的锁函数
+// 持有期间定期刷新locked_at, 持有者异常退出后锁在lockTtl后失效
+func (m *migratorImpl) withLock(ctx context.Context, fn func() error) error {
+	// dry run不修改数据库, 不需要建表和加锁
+	if m.dryRun {
+		return fn()
+	}
+
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.NewString())
+	if err := m.lock(ctx, owner); err != nil {
+		return err
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.Background())
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		m.heartbeat(heartbeatCtx, owner)
+	}()
+
+	defer func() {
+		stopHeartbeat()
+		<-heartbeatDone
+
+		// 使用新的ctx, 保证ctx取消后仍然可以释放锁
+		_, err := m.client.ExecContext(context.Background(), m.client.Rebind(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND owner = ?", m.lockTable)), lockId, owner)
+		if err != nil {
+			m.log("release migration lock", "err", err)
+		}
+	}()
+
+	return fn()
+}
+
+func (m *migratorImpl) lock(ctx context.Context, owner string) error {
+	insertQuery := m.client.Rebind(fmt.Sprintf("INSERT INTO %s (id, owner, locked_at) VALUES (?, ?, ?)", m.lockTable))
+	expireQuery := m.client.Rebind(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND locked_at < ?", m.lockTable))
+
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		_, err := m.client.ExecContext(ctx, insertQuery, lockId, owner, time.Now().Unix())
+		if err == nil {
+			return nil
+		}
+
+		// 只有主键冲突说明锁被其他副本持有, 其他错误直接返回
+		if !db.IsDuplicateKeyError(err) {
+			return errors.Wrap(err, "acquire migration lock")
+		}
+
+		// 清理过期的锁
+		_, err = m.client.ExecContext(ctx, expireQuery, lockId, time.Now().Add(-m.lockTtl).Unix())
+		if err != nil {
+			return errors.Wrap(err, "expire migration lock")
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("acquire migration lock timeout")
+		}
+
+		m.log("wait for migration lock", "owner", owner)
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "acquire migration lock")
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
+
+// heartbeat 定期刷新locked_at, 避免执行时间超过lockTtl的迁移被其他副本认为锁已过期
+func (m *migratorImpl) heartbeat(ctx context.Context, owner string) {
+	refreshQuery := m.client.Rebind(fmt.Sprintf("UPDATE %s SET locked_at = ? WHERE id = ? AND owner = ?", m.lockTable))
+
+	ticker := time.NewTicker(m.lockTtl / lockHeartbeatRatio)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := m.client.ExecContext(ctx, refreshQuery, time.Now().Unix(), lockId, owner)
+			if err != nil {
+				m.log("refresh migration lock", "owner", owner, "err", err)
+				continue
+			}
+
+			if n, _ := result.RowsAffected(); n == 0 {
+				m.log("migration lock lost", "owner", owner)
+			}
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/hdget/hdutils/logger"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type Migrator interface {
+	// Up 执行所有未执行的迁移
+	Up(ctx context.Context) ([]*Migration, error)
+	// To 迁移到指定版本, 比当前版本新则执行up, 比当前版本旧则依次执行down直到该版本, 0表示回滚所有迁移
+	To(ctx context.Context, version uint64) ([]*Migration, error)
+	// Down 回滚最近一次执行的迁移
+	Down(ctx context.Context) (*Migration, error)
+	// Version 当前已执行的最大版本, 没有执行过迁移时返回0
+	Version(ctx context.Context) (uint64, error)
+	// Status 所有迁移及其是否已执行
+	Status(ctx context.Context) ([]*MigrationStatus, error)
+}
+
+type MigrationStatus struct {
+	*Migration
+	Applied   bool
+	AppliedAt int64 // unix timestamp
+}
+
+type migratorImpl struct {
+	*migratorOption
+	client     intf.DbClient
+	migrations []*Migration
+}
+
+// New 从fs中加载迁移文件, 在client上执行迁移, client可以是hdsdk.Db().My()或者hdsdk.Db().By(name)
+func New(client intf.DbClient, fs embed.FS, options ...Option) (Migrator, error) {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	// 持有者异常退出后锁在lockTtl后才失效, locked_at精确到秒, 等待时间太短的话所有副本都会启动失败
+	o.lockTimeout = max(o.lockTimeout, 2*o.lockTtl)
+
+	migrations, err := loadMigrations(fs, o.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migratorImpl{
+		migratorOption: o,
+		client:         client,
+		migrations:     migrations,
+	}, nil
+}
+
+func (m *migratorImpl) Up(ctx context.Context) ([]*Migration, error) {
+	if len(m.migrations) == 0 {
+		return nil, nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+func (m *migratorImpl) To(ctx context.Context, version uint64) ([]*Migration, error) {
+	var executed []*Migration
+	err := m.withLock(ctx, func() error {
+		applied, err := m.getApplied(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 先回滚比目标版本新的迁移, 从新到旧
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			if err = m.runDown(ctx, migration); err != nil {
+				return err
+			}
+			executed = append(executed, migration)
+		}
+
+		// 再执行不超过目标版本且没有执行过的迁移, 从旧到新
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err = m.runUp(ctx, migration); err != nil {
+				return err
+			}
+			executed = append(executed, migration)
+		}
+		return nil
+	})
+	return executed, err
+}
+
+func (m *migratorImpl) Down(ctx context.Context) (*Migration, error) {
+	var executed *Migration
+	err := m.withLock(ctx, func() error {
+		applied, err := m.getApplied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				executed = m.migrations[i]
+				return m.runDown(ctx, executed)
+			}
+		}
+		return nil
+	})
+	return executed, err
+}
+
+func (m *migratorImpl) Version(ctx context.Context) (uint64, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return 0, err
+	}
+
+	if !m.versionTableExists(ctx) {
+		return 0, nil
+	}
+
+	var version uint64
+	err := m.client.GetContext(ctx, &version, fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s", m.table))
+	if err != nil {
+		return 0, errors.Wrap(err, "get current version")
+	}
+	return version, nil
+}
+
+func (m *migratorImpl) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.getApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses[i] = &MigrationStatus{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		}
+	}
+	return statuses, nil
+}
+
+func (m *migratorImpl) runUp(ctx context.Context, migration *Migration) error {
+	m.log("migrate up", "version", migration.Version, "name", migration.Name, "dry_run", m.dryRun)
+	return m.run(ctx, migration, migration.Up,
+		m.client.Rebind(fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", m.table)),
+		migration.Version, migration.Name, time.Now().Unix(),
+	)
+}
+
+func (m *migratorImpl) runDown(ctx context.Context, migration *Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration missing down sql, version: %d", migration.Version)
+	}
+
+	m.log("migrate down", "version", migration.Version, "name", migration.Name, "dry_run", m.dryRun)
+	return m.run(ctx, migration, migration.Down,
+		m.client.Rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.table)),
+		migration.Version,
+	)
+}
+
+// run 在事务中执行迁移SQL并更新版本表, MySQL的DDL会隐式提交, 所以执行失败时需要人工处理
+func (m *migratorImpl) run(ctx context.Context, migration *Migration, content, versionQuery string, versionArgs ...any) error {
+	statements := splitStatements(content)
+	if m.dryRun {
+		for _, stmt := range statements {
+			m.log("dry run", "version", migration.Version, "sql", stmt)
+		}
+		return nil
+	}
+
+	err := db.InTx(ctx, m.client, func(ctx context.Context) error {
+		executor := db.Executor(ctx, m.client)
+		for _, stmt := range statements {
+			if _, err := executor.ExecContext(ctx, stmt); err != nil {
+				return errors.Wrapf(err, "exec sql: %s", stmt)
+			}
+		}
+
+		_, err := executor.ExecContext(ctx, versionQuery, versionArgs...)
+		return err
+	}, db.WithTxMaxRetries(0))
+	if err != nil {
+		return errors.Wrapf(err, "migrate, version: %d, name: %s", migration.Version, migration.Name)
+	}
+	return nil
+}
+
+// getApplied 返回已执行的版本=>执行时间
+func (m *migratorImpl) getApplied(ctx context.Context) (map[uint64]int64, error) {
+	if !m.versionTableExists(ctx) {
+		return map[uint64]int64{}, nil
+	}
+
+	var rows []struct {
+		Version   uint64 `db:"version"`
+		AppliedAt int64  `db:"applied_at"`
+	}
+
+	err := m.client.SelectContext(ctx, &rows, fmt.Sprintf("SELECT version, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, errors.Wrap(err, "get applied versions")
+	}
+
+	applied := make(map[uint64]int64, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// ensureTables 创建版本表和锁表, 使用MySQL, SQLite和PostgreSQL都支持的语法, dry run时不建表
+func (m *migratorImpl) ensureTables(ctx context.Context) error {
+	if m.dryRun {
+		return nil
+	}
+
+	queries := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at BIGINT NOT NULL)", m.table),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id INT NOT NULL PRIMARY KEY, owner VARCHAR(255) NOT NULL, locked_at BIGINT NOT NULL)", m.lockTable),
+	}
+
+	for _, query := range queries {
+		if _, err := m.client.ExecContext(ctx, query); err != nil {
+			return errors.Wrap(err, "create migration table")
+		}
+	}
+	return nil
+}
+
+// versionTableExists 只有dry run时版本表可能不存在, 视为没有执行过迁移
+func (m *migratorImpl) versionTableExists(ctx context.Context) bool {
+	if !m.dryRun {
+		return true
+	}
+
+	rows, err := m.client.QueryContext(ctx, fmt.Sprintf("SELECT 1 FROM %s WHERE 1 = 0", m.table))
+	if err != nil {
+		return false
+	}
+	_ = rows.Close()
+	return true
+}
+
+func (m *migratorImpl) log(msg string, kvs ...any) {
+	if m.logger != nil {
+		m.logger.Info(msg, kvs...)
+		return
+	}
+	logger.Debug(msg, kvs...)
+}
@@ -0,0 +1,126 @@
+package migrate
+
+import (
+	"embed"
+	"fmt"
+	"github.com/pkg/errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration 一个版本的迁移, 文件名格式为<version>_<name>.up.sql和<version>_<name>.down.sql
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+var (
+	regexMigrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+)
+
+// loadMigrations 从embed.FS中加载迁移文件, 按版本号升序排列
+func loadMigrations(fs embed.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read migration dir, dir: %s", dir)
+	}
+
+	version2migration := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := regexMigrationFile.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration version, file: %s", entry.Name())
+		}
+
+		// IMPORTANT: embedfs使用的是斜杠来获取文件路径,在windows平台下如果使用filepath来处理路径会导致问题
+		content, err := fs.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "read migration file, file: %s", entry.Name())
+		}
+
+		m, exists := version2migration[version]
+		if !exists {
+			m = &Migration{Version: version, Name: matches[2]}
+			version2migration[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate migration version, version: %d", version)
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(version2migration))
+	for _, m := range version2migration {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration missing up sql, version: %d", m.Version)
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements 按行尾的分号拆分多条SQL语句, 忽略引号中的分号和--注释
+// 不支持存储过程和触发器等语句体中包含分号的情况
+func splitStatements(content string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      rune
+	)
+
+	flush := func() {
+		stmt := strings.TrimSpace(current.String())
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if quote == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		for _, r := range line {
+			switch {
+			case quote != 0 && r == quote:
+				quote = 0
+			case quote == 0 && (r == '\'' || r == '"' || r == '`'):
+				quote = r
+			}
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if quote == 0 && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+
+	return statements
+}
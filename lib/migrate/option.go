@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"time"
+)
+
+type Option func(*migratorOption)
+
+type migratorOption struct {
+	dir          string        // embed.FS中迁移文件所在的目录
+	table        string        // 记录已执行版本的表
+	lockTable    string        // 迁移锁表
+	lockTimeout  time.Duration // 等待迁移锁的最长时间, 不小于2倍lockTtl, 保证持有者异常退出后等待的副本可以获取到锁
+	lockTtl      time.Duration // 迁移锁超过该时间未刷新则认为持有者已经退出
+	dryRun       bool          // 只打印需要执行的SQL, 不实际执行
+	logger       intf.LoggerProvider
+	pollInterval time.Duration // 等待迁移锁时的轮询间隔
+}
+
+const (
+	defaultDir          = "migrations"
+	defaultTable        = "schema_migrations"
+	defaultLockTimeout  = 5 * time.Minute
+	defaultLockTtl      = time.Minute
+	defaultPollInterval = time.Second
+)
+
+func newOption() *migratorOption {
+	return &migratorOption{
+		dir:          defaultDir,
+		table:        defaultTable,
+		lockTable:    defaultTable + "_lock",
+		lockTimeout:  defaultLockTimeout,
+		lockTtl:      defaultLockTtl,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+func WithDir(dir string) Option {
+	return func(o *migratorOption) {
+		o.dir = dir
+	}
+}
+
+// WithTable 设置记录版本的表名, 锁表名为<table>_lock
+func WithTable(table string) Option {
+	return func(o *migratorOption) {
+		o.table = table
+		o.lockTable = table + "_lock"
+	}
+}
+
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(o *migratorOption) {
+		o.lockTimeout = timeout
+	}
+}
+
+func WithLockTtl(ttl time.Duration) Option {
+	return func(o *migratorOption) {
+		o.lockTtl = ttl
+	}
+}
+
+func WithDryRun() Option {
+	return func(o *migratorOption) {
+		o.dryRun = true
+	}
+}
+
+func WithLogger(logger intf.LoggerProvider) Option {
+	return func(o *migratorOption) {
+		o.logger = logger
+	}
+}
@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"github.com/hdget/hdsdk/v2"
+	"github.com/pkg/errors"
+)
+
+// OnStartup 返回在hdsdk Initialize时执行的数据库迁移步骤, name为空时使用hdsdk.Db().My(), 否则使用hdsdk.Db().By(name)
+// e,g: hdsdk.New(app, env).LoadConfig(&c).OnStartup(migrate.OnStartup(migrations, "")).Initialize(...)
+func OnStartup(fs embed.FS, name string, options ...Option) hdsdk.StartupHook {
+	return func(ctx context.Context) error {
+		if hdsdk.Db() == nil {
+			return errors.New("sdk db not initialized")
+		}
+
+		client := hdsdk.Db().My()
+		if name != "" {
+			client = hdsdk.Db().By(name)
+		}
+
+		if client == nil {
+			return errors.Errorf("db client not found, name: %s", name)
+		}
+
+		if hdsdk.Logger() != nil {
+			options = append([]Option{WithLogger(hdsdk.Logger())}, options...)
+		}
+
+		m, err := New(client, fs, options...)
+		if err != nil {
+			return errors.Wrap(err, "new migrator")
+		}
+
+		_, err = m.Up(ctx)
+		return err
+	}
+}
@@ -0,0 +1,313 @@
+package pagination
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SortKey 排序字段, Column可以带表别名, e,g: u.created_at
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Keyset 基于游标的分页, 排序字段的组合必须唯一, 一般最后一个字段为主键, 否则可能漏掉或重复数据
+// 使用方式:
+// 1. 查询时加上Where()的条件, 按OrderBy()排序, 取Limit()条数据
+// 2. 查询结果传给Paginate, 去掉多取的一条数据并生成前后页的游标
+type Keyset struct {
+	keys     []SortKey
+	pageSize int64
+	cursor   *keysetCursor
+}
+
+// KeysetPage 分页结果, 游标为空表示没有前一页/后一页
+type KeysetPage struct {
+	NextCursor string
+	PrevCursor string
+}
+
+// keysetCursor 游标中保存边界行的排序字段值, Backward为true表示向前翻页
+// Types和Values一一对应, 记录json无法还原的值的类型, 没有时为空
+type keysetCursor struct {
+	Values   []any    `json:"v"`
+	Types    []string `json:"t,omitempty"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+const (
+	cursorTypeTime = "time" // time.Time按RFC3339Nano格式保存, 解码时还原为time.Time
+)
+
+var (
+	type2columnIndexes sync.Map // reflect.Type => map[column][]int
+)
+
+// NewKeyset cursor为上一次返回的NextCursor或PrevCursor, 为空表示第一页
+func NewKeyset(cursor string, pageSize int64, keys ...SortKey) (*Keyset, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("sort keys required")
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	ks := &Keyset{keys: keys, pageSize: pageSize}
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(c.Values) != len(keys) {
+			return nil, errors.New("cursor does not match sort keys")
+		}
+		ks.cursor = c
+	}
+	return ks, nil
+}
+
+// Where 游标对应的条件, 第一页返回空, 占位符为?
+// e,g: (a > ?) OR (a = ? AND b < ?)
+func (ks *Keyset) Where() (string, []any) {
+	if ks.cursor == nil {
+		return "", nil
+	}
+
+	var (
+		ors  []string
+		args []any
+	)
+	for i, key := range ks.keys {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, fmt.Sprintf("%s = ?", ks.keys[j].Column))
+			args = append(args, ks.cursor.Values[j])
+		}
+
+		op := ">"
+		if key.Desc != ks.cursor.Backward {
+			op = "<"
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", key.Column, op))
+		args = append(args, ks.cursor.Values[i])
+
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// OrderBy 排序语句, 不包含ORDER BY, 向前翻页时排序方向相反
+func (ks *Keyset) OrderBy() string {
+	backward := ks.cursor != nil && ks.cursor.Backward
+	orders := make([]string, len(ks.keys))
+	for i, key := range ks.keys {
+		if key.Desc != backward {
+			orders[i] = key.Column + " DESC"
+		} else {
+			orders[i] = key.Column + " ASC"
+		}
+	}
+	return strings.Join(orders, ", ")
+}
+
+// Limit 多取一条数据用于判断是否还有数据
+func (ks *Keyset) Limit() int64 {
+	return ks.pageSize + 1
+}
+
+// Paginate dest为查询结果的slice指针, 去掉多取的数据, 向前翻页时恢复正常顺序, 返回前后页的游标
+// 通过db或boil标签找到排序字段对应的结构体字段
+func (ks *Keyset) Paginate(dest any) (*KeysetPage, error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("dest must be pointer to slice")
+	}
+	v = v.Elem()
+
+	backward := ks.cursor != nil && ks.cursor.Backward
+	hasMore := int64(v.Len()) > ks.pageSize
+	if hasMore {
+		v.Set(v.Slice(0, int(ks.pageSize)))
+	}
+
+	if backward {
+		reflectReverse(v)
+	}
+
+	page := &KeysetPage{}
+	if v.Len() == 0 {
+		return page, nil
+	}
+
+	// 向后翻页时, 有更多数据才有下一页, 从其他页翻过来的才有前一页, 向前翻页相反
+	hasNext, hasPrev := hasMore, ks.cursor != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		values, err := ks.getKeyValues(v.Index(v.Len() - 1))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = encodeCursor(&keysetCursor{Values: values})
+	}
+
+	if hasPrev {
+		values, err := ks.getKeyValues(v.Index(0))
+		if err != nil {
+			return nil, err
+		}
+		page.PrevCursor = encodeCursor(&keysetCursor{Values: values, Backward: true})
+	}
+	return page, nil
+}
+
+func (ks *Keyset) getKeyValues(row reflect.Value) ([]any, error) {
+	for row.Kind() == reflect.Pointer {
+		row = row.Elem()
+	}
+
+	if row.Kind() != reflect.Struct {
+		return nil, errors.New("row must be struct")
+	}
+
+	column2index := getColumnIndexes(row.Type())
+	values := make([]any, len(ks.keys))
+	for i, key := range ks.keys {
+		// 去掉表别名
+		column := key.Column
+		if pos := strings.LastIndex(column, "."); pos >= 0 {
+			column = column[pos+1:]
+		}
+
+		index, ok := column2index[column]
+		if !ok {
+			return nil, errors.Errorf("sort key field not found, column: %s", key.Column)
+		}
+
+		value := row.FieldByIndex(index).Interface()
+		// sql.NullTime, null.Time等类型取出实际的值
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return nil, errors.Wrapf(err, "get sort key value, column: %s", key.Column)
+			}
+			value = v
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func getColumnIndexes(t reflect.Type) map[string][]int {
+	if cached, ok := type2columnIndexes.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	column2index := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+
+		for _, tag := range []string{"db", "boil"} {
+			if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+				column2index[name] = f.Index
+				break
+			}
+		}
+	}
+
+	type2columnIndexes.Store(t, column2index)
+	return column2index
+}
+
+func reflectReverse(v reflect.Value) {
+	swap := reflect.Swapper(v.Interface())
+	for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// encodeCursor 游标对调用方不透明
+func encodeCursor(c *keysetCursor) string {
+	for i, value := range c.Values {
+		var t time.Time
+		switch v := value.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v == nil {
+				continue
+			}
+			t = *v
+		default:
+			continue
+		}
+
+		if c.Types == nil {
+			c.Types = make([]string, len(c.Values))
+		}
+		c.Types[i] = cursorTypeTime
+		c.Values[i] = t.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*keysetCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+
+	// 使用json.Number避免大整数丢失精度
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var c keysetCursor
+	if err = decoder.Decode(&c); err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+
+	if len(c.Types) > 0 && len(c.Types) != len(c.Values) {
+		return nil, errors.New("invalid cursor")
+	}
+
+	for i, value := range c.Values {
+		if len(c.Types) > 0 && c.Types[i] == cursorTypeTime {
+			str, ok := value.(string)
+			if !ok {
+				return nil, errors.New("invalid cursor")
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid cursor")
+			}
+			c.Values[i] = t
+			continue
+		}
+
+		if n, ok := value.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				c.Values[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				c.Values[i] = fv
+			}
+		}
+	}
+	return &c, nil
+}
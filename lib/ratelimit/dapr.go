@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/dapr/go-sdk/service/common"
+	"github.com/hdget/hdsdk/v2/lib/bizerr"
+	"github.com/hdget/hdsdk/v2/lib/dapr"
+	"github.com/hdget/hdutils/logger"
+	"strconv"
+)
+
+// InvocationKeyFunc 从服务调用中获取限流的key, 返回空字符串表示不限流
+type InvocationKeyFunc func(ctx context.Context, event *common.InvocationEvent) string
+
+// WrapInvocation 为dapr服务调用函数增加限流, 超过限制时返回ErrCodeTooManyRequests业务错误, 限流器出错时放行
+//
+// e,g:
+//
+//	limiter := ratelimit.NewFixedWindow(hdsdk.Redis().My(), 1, time.Minute, ratelimit.WithKeyPrefix("ratelimit:sms:"))
+//	functions := map[string]dapr.InvocationFunction{
+//		"sendSms": ratelimit.WrapInvocation(limiter, ratelimit.ByTenant, m.SendSmsHandler),
+//	}
+func WrapInvocation(limiter Limiter, keyFunc InvocationKeyFunc, fn dapr.InvocationFunction) dapr.InvocationFunction {
+	return func(ctx context.Context, event *common.InvocationEvent) (any, error) {
+		key := keyFunc(ctx, event)
+		if key == "" {
+			return fn(ctx, event)
+		}
+
+		result, err := limiter.Allow(ctx, key)
+		if err != nil {
+			logger.Error("rate limit", "key", key, "err", err)
+			return fn(ctx, event)
+		}
+
+		if !result.Allowed {
+			return nil, bizerr.New(bizerr.ErrCodeTooManyRequests, ErrLimitExceeded.Error())
+		}
+
+		return fn(ctx, event)
+	}
+}
+
+// ByTenant 按租户限流, 不同的服务调用应该通过WithKeyPrefix使用不同的限流器
+func ByTenant(ctx context.Context, _ *common.InvocationEvent) string {
+	tid := dapr.Meta().GetTenantId(ctx)
+	if tid == 0 {
+		return ""
+	}
+	return "tenant:" + strconv.FormatInt(tid, 10)
+}
+
+// ByUser 按用户限流, 不同的服务调用应该通过WithKeyPrefix使用不同的限流器
+func ByUser(ctx context.Context, _ *common.InvocationEvent) string {
+	uid := dapr.Meta().GetUserId(ctx)
+	if uid == 0 {
+		return ""
+	}
+	return "user:" + strconv.FormatInt(uid, 10)
+}
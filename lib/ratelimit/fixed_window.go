@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"time"
+)
+
+const (
+	// KEYS[1]: 计数key, ARGV[1]: 配额上限, ARGV[2]: 窗口大小(毫秒), ARGV[3]: 消耗的配额
+	luaFixedWindow = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	ttl = window
+end
+
+if current + n > limit then
+	return {0, limit - current, ttl, ttl}
+end
+
+current = redis.call('INCRBY', KEYS[1], n)
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], window)
+	ttl = window
+end
+return {1, limit - current, ttl, 0}
+`
+)
+
+var (
+	scriptFixedWindow = redis.NewScript(1, luaFixedWindow)
+)
+
+// NewFixedWindow 固定窗口限流, 每个window时间内最多允许limit次
+func NewFixedWindow(client intf.RedisClient, limit int64, window time.Duration, options ...Option) Limiter {
+	return newRedisLimiter(client, scriptFixedWindow, limit, func() []any {
+		return []any{limit, window.Milliseconds()}
+	}, options...)
+}
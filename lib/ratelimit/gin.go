@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hdget/hdsdk/v2/lib/bizerr"
+	"github.com/hdget/hdutils/logger"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GinKeyFunc 从http请求中获取限流的key, 返回空字符串表示不限流
+type GinKeyFunc func(c *gin.Context) string
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRetryAfter         = "Retry-After"
+)
+
+// GinMiddleware gin限流中间件, 超过限制时返回429, 限流器出错时放行
+//
+// e,g:
+//
+//	limiter := ratelimit.NewSlidingWindow(hdsdk.Redis().My(), 5, time.Minute)
+//	router.POST("/login", ratelimit.GinMiddleware(limiter, ratelimit.ByClientIP), loginHandler)
+func GinMiddleware(limiter Limiter, keyFunc GinKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), key)
+		if err != nil {
+			logger.Error("rate limit", "key", key, "err", err)
+			c.Next()
+			return
+		}
+
+		c.Header(headerRateLimitLimit, strconv.FormatInt(result.Limit, 10))
+		c.Header(headerRateLimitRemaining, strconv.FormatInt(result.Remaining, 10))
+		c.Header(headerRateLimitReset, strconv.FormatInt(toSeconds(result.ResetAfter), 10))
+		if !result.Allowed {
+			c.Header(headerRetryAfter, strconv.FormatInt(toSeconds(result.RetryAfter), 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, bizerr.New(bizerr.ErrCodeTooManyRequests, ErrLimitExceeded.Error()))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ByClientIP 按客户端IP限流
+func ByClientIP(c *gin.Context) string {
+	return c.FullPath() + ":" + c.ClientIP()
+}
+
+func toSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,23 @@
+package ratelimit
+
+type Option func(*limiterOption)
+
+type limiterOption struct {
+	prefix string // redis key前缀
+}
+
+const (
+	defaultKeyPrefix = "ratelimit:"
+)
+
+func newOption() *limiterOption {
+	return &limiterOption{
+		prefix: defaultKeyPrefix,
+	}
+}
+
+func WithKeyPrefix(prefix string) Option {
+	return func(o *limiterOption) {
+		o.prefix = prefix
+	}
+}
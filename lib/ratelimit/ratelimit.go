@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Limiter 限流器
+type Limiter interface {
+	Allow(ctx context.Context, key string) (*Result, error)           // 消耗1个配额
+	AllowN(ctx context.Context, key string, n int64) (*Result, error) // 消耗n个配额
+}
+
+// Result 限流结果
+type Result struct {
+	Allowed    bool          // 是否允许
+	Limit      int64         // 配额上限
+	Remaining  int64         // 剩余配额
+	ResetAfter time.Duration // 多久后配额完全恢复
+	RetryAfter time.Duration // 被拒绝时, 多久后可以重试
+}
+
+type redisLimiter struct {
+	client intf.RedisClient
+	option *limiterOption
+	limit  int64
+	script *redis.Script
+	args   func() []any // 生成除了消耗配额以外的脚本参数
+}
+
+var (
+	ErrLimitExceeded = errors.New("rate limit exceeded")
+)
+
+func newRedisLimiter(client intf.RedisClient, script *redis.Script, limit int64, args func() []any, options ...Option) *redisLimiter {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	return &redisLimiter{
+		client: client,
+		option: o,
+		limit:  limit,
+		script: script,
+		args:   args,
+	}
+}
+
+func (b *redisLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return b.AllowN(ctx, key, 1)
+}
+
+// AllowN 执行限流脚本, 所有限流脚本的最后一个参数都是消耗的配额, 都返回: {allowed, remaining, reset_ms, retry_ms}
+func (b *redisLimiter) AllowN(_ context.Context, key string, n int64) (*Result, error) {
+	if n <= 0 {
+		return nil, errors.New("invalid number of tokens")
+	}
+
+	values, err := redis.Int64s(b.script.Run(b.client, []any{b.option.prefix + key}, append(b.args(), n)...))
+	if err != nil {
+		return nil, errors.Wrapf(err, "run rate limit script, key: %s", key)
+	}
+
+	if len(values) != 4 {
+		return nil, errors.Errorf("invalid rate limit script reply, reply: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      b.limit,
+		Remaining:  max(values[1], 0),
+		ResetAfter: time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"github.com/google/uuid"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+	"time"
+)
+
+const (
+	// 滑动窗口日志, 用有序集合记录窗口内每次请求的时间
+	// KEYS[1]: 日志key, ARGV[1]: 配额上限, ARGV[2]: 窗口大小(毫秒), ARGV[3]: 请求唯一标识, ARGV[4]: 消耗的配额
+	luaSlidingWindow = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local id = ARGV[3]
+local n = tonumber(ARGV[4])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+
+local reset = 0
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+if newest[2] then
+	reset = tonumber(newest[2]) + window - now
+end
+
+if count + n > limit then
+	local retry = window
+	local index = count + n - limit - 1
+	if index < count then
+		local entry = redis.call('ZRANGE', KEYS[1], index, index, 'WITHSCORES')
+		if entry[2] then
+			retry = tonumber(entry[2]) + window - now
+		end
+	end
+	return {0, limit - count, reset, retry}
+end
+
+for i = 1, n do
+	redis.call('ZADD', KEYS[1], now, id .. ':' .. i)
+end
+redis.call('PEXPIRE', KEYS[1], window)
+return {1, limit - count - n, window, 0}
+`
+)
+
+var (
+	scriptSlidingWindow = redis.NewScript(1, luaSlidingWindow)
+)
+
+// NewSlidingWindow 滑动窗口日志限流, 任意window时间内最多允许limit次
+func NewSlidingWindow(client intf.RedisClient, limit int64, window time.Duration, options ...Option) Limiter {
+	return newRedisLimiter(client, scriptSlidingWindow, limit, func() []any {
+		return []any{limit, window.Milliseconds(), uuid.NewString()}
+	}, options...)
+}
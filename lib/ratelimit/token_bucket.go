@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/redis"
+)
+
+const (
+	// 令牌桶, 按rate匀速补充令牌, 桶容量为burst
+	// KEYS[1]: 桶key, ARGV[1]: 每秒补充的令牌数, ARGV[2]: 桶容量, ARGV[3]: 消耗的令牌数
+	luaTokenBucket = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	retry = math.ceil((n - tokens) * 1000 / rate)
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate))
+return {allowed, math.floor(tokens), math.ceil((burst - tokens) * 1000 / rate), retry}
+`
+)
+
+const (
+	// minTokenBucketRate 最小的令牌补充速率, 脚本中需要除以rate
+	minTokenBucketRate = 0.001
+)
+
+var (
+	scriptTokenBucket = redis.NewScript(1, luaTokenBucket)
+)
+
+// NewTokenBucket 令牌桶限流, 每秒补充rate个令牌, 最多积累burst个令牌, rate小于minTokenBucketRate时按minTokenBucketRate处理
+func NewTokenBucket(client intf.RedisClient, rate float64, burst int64, options ...Option) Limiter {
+	rate = max(rate, minTokenBucketRate)
+	return newRedisLimiter(client, scriptTokenBucket, burst, func() []any {
+		return []any{rate, burst}
+	}, options...)
+}
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"encoding/json"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"reflect"
+)
+
+// Codec 存入redis的值的编解码器
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonCodec struct{}
+
+type msgpackCodec struct{}
+
+type protoCodec struct{}
+
+var (
+	JSONCodec    Codec = jsonCodec{}
+	MsgpackCodec Codec = msgpackCodec{}
+	ProtoCodec   Codec = protoCodec{} // 值必须实现proto.Message
+)
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+func (protoCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("value is not proto message, type: %T", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (protoCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return errors.Errorf("value is not proto message, type: %T", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+func getCodec(codecs ...Codec) Codec {
+	if len(codecs) > 0 && codecs[0] != nil {
+		return codecs[0]
+	}
+	return JSONCodec
+}
+
+func encode[T any](codec Codec, value T) (string, error) {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "encode value")
+	}
+	return string(data), nil
+}
+
+func encodeAll[T any](codec Codec, values []T) ([]string, error) {
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		s, err := encode(codec, v)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = s
+	}
+	return encoded, nil
+}
+
+// Decode 解码数据, 如果T是指针类型, 会自动分配内存
+func Decode[T any](codec Codec, data []byte) (T, error) {
+	var v T
+	err := codec.Unmarshal(data, newTarget(&v))
+	if err != nil {
+		return v, errors.Wrap(err, "decode value")
+	}
+	return v, nil
+}
+
+func decodeAll[T any](codec Codec, items []string) ([]T, error) {
+	values := make([]T, len(items))
+	for i, item := range items {
+		v, err := Decode[T](codec, []byte(item))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// newTarget 获取解码目标, 如果ptr指向的是nil指针, 则分配内存后返回该指针, 以便proto.Message这类指针类型可以直接解码
+func newTarget(ptr any) any {
+	rv := reflect.ValueOf(ptr).Elem()
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		return rv.Interface()
+	}
+	return ptr
+}
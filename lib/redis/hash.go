@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+)
+
+// Hash 值类型为T的redis hash
+type Hash[T any] struct {
+	client intf.RedisClient
+	codec  Codec
+	key    string
+}
+
+// NewHash 创建类型化的hash, 缺省使用JSONCodec
+func NewHash[T any](client intf.RedisClient, key string, codecs ...Codec) *Hash[T] {
+	return &Hash[T]{
+		client: client,
+		codec:  getCodec(codecs...),
+		key:    key,
+	}
+}
+
+// Get 获取field的值, field不存在时found为false且err为nil
+func (h *Hash[T]) Get(field string) (value T, found bool, err error) {
+	data, err := h.client.HGet(h.key, field)
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return value, false, nil
+		}
+		return value, false, err
+	}
+
+	value, err = Decode[T](h.codec, data)
+	if err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+// Set 设置field的值
+func (h *Hash[T]) Set(field string, value T) error {
+	s, err := encode(h.codec, value)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.client.HSet(h.key, field, s)
+	return err
+}
+
+// MGet 获取多个field的值, 不存在的field不会出现在返回结果中
+func (h *Hash[T]) MGet(fields ...string) (map[string]T, error) {
+	items, err := h.client.HMGet(h.key, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]T)
+	for i, data := range items {
+		if data == nil || i >= len(fields) {
+			continue
+		}
+
+		v, err := Decode[T](h.codec, data)
+		if err != nil {
+			return nil, err
+		}
+		values[fields[i]] = v
+	}
+	return values, nil
+}
+
+// MSet 设置多个field的值
+func (h *Hash[T]) MSet(values map[string]T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fieldValues := make(map[string]any, len(values))
+	for field, v := range values {
+		s, err := encode(h.codec, v)
+		if err != nil {
+			return err
+		}
+		fieldValues[field] = s
+	}
+	return h.client.HMSet(h.key, fieldValues)
+}
+
+// All 获取所有field的值
+func (h *Hash[T]) All() (map[string]T, error) {
+	items, err := h.client.HGetAll(h.key)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]T, len(items))
+	for field, data := range items {
+		v, err := Decode[T](h.codec, []byte(data))
+		if err != nil {
+			return nil, err
+		}
+		values[field] = v
+	}
+	return values, nil
+}
+
+// Del 删除field
+func (h *Hash[T]) Del(fields ...string) (int, error) {
+	args := make([]any, len(fields))
+	for i, field := range fields {
+		args[i] = field
+	}
+	return h.client.HDels(h.key, args)
+}
+
+// Len 获取field的数量
+func (h *Hash[T]) Len() (int, error) {
+	return h.client.HLen(h.key)
+}
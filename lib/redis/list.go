@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+)
+
+// List 元素类型为T的redis列表
+type List[T any] struct {
+	client intf.RedisClient
+	codec  Codec
+	key    string
+}
+
+// NewList 创建类型化的列表, 缺省使用JSONCodec
+func NewList[T any](client intf.RedisClient, key string, codecs ...Codec) *List[T] {
+	return &List[T]{
+		client: client,
+		codec:  getCodec(codecs...),
+		key:    key,
+	}
+}
+
+// LPush 从头部插入元素
+func (l *List[T]) LPush(values ...T) error {
+	args, err := l.encodeArgs(values)
+	if err != nil {
+		return err
+	}
+	return l.client.LPush(l.key, args...)
+}
+
+// RPush 从尾部插入元素
+func (l *List[T]) RPush(values ...T) error {
+	args, err := l.encodeArgs(values)
+	if err != nil {
+		return err
+	}
+	return l.client.RPush(l.key, args...)
+}
+
+// LPop 移除并返回第一个元素, 列表为空时found为false且err为nil
+func (l *List[T]) LPop() (value T, found bool, err error) {
+	return l.decodePopped(l.client.LPop(l.key))
+}
+
+// RPop 移除并返回最后一个元素, 列表为空时found为false且err为nil
+func (l *List[T]) RPop() (value T, found bool, err error) {
+	return l.decodePopped(l.client.RPop(l.key))
+}
+
+// Trim 只保留指定范围内的元素
+func (l *List[T]) Trim(start, end int64) error {
+	return l.client.LTrim(l.key, start, end)
+}
+
+func (l *List[T]) decodePopped(data []byte, err error) (T, bool, error) {
+	var value T
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return value, false, nil
+		}
+		return value, false, err
+	}
+
+	value, err = Decode[T](l.codec, data)
+	if err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+// Range 获取指定范围内的元素
+func (l *List[T]) Range(start, end int64) ([]T, error) {
+	items, err := l.client.LRangeString(l.key, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](l.codec, items)
+}
+
+// Len 获取列表长度
+func (l *List[T]) Len() (int64, error) {
+	return l.client.LLen(l.key)
+}
+
+func (l *List[T]) encodeArgs(values []T) ([]any, error) {
+	encoded, err := encodeAll(l.codec, values)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]any, len(encoded))
+	for i, v := range encoded {
+		args[i] = v
+	}
+	return args, nil
+}
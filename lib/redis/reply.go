@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrNil redis返回nil时的错误, 比如key不存在
+var ErrNil = redis.ErrNil
+
+// Int64 将脚本或命令的返回值转换为int64
+func Int64(reply any, err error) (int64, error) {
+	return redis.Int64(reply, err)
+}
+
+// Int64s 将脚本或命令的返回值转换为[]int64
+func Int64s(reply any, err error) ([]int64, error) {
+	return redis.Int64s(reply, err)
+}
+
+// Float64 将脚本或命令的返回值转换为float64
+func Float64(reply any, err error) (float64, error) {
+	return redis.Float64(reply, err)
+}
+
+// Bool 将脚本或命令的返回值转换为bool
+func Bool(reply any, err error) (bool, error) {
+	return redis.Bool(reply, err)
+}
+
+// String 将脚本或命令的返回值转换为string
+func String(reply any, err error) (string, error) {
+	return redis.String(reply, err)
+}
+
+// Strings 将脚本或命令的返回值转换为[]string
+func Strings(reply any, err error) ([]string, error) {
+	return redis.Strings(reply, err)
+}
+
+// Map 将脚本或命令返回的field/value数组转换为map[string]string
+func Map(reply any, err error) (map[string]string, error) {
+	return redis.StringMap(reply, err)
+}
+
+// Values 将脚本或命令的返回值转换为[]any
+func Values(reply any, err error) ([]any, error) {
+	return redis.Values(reply, err)
+}
+
+// Bytes 将脚本或命令的返回值转换为[]byte
+func Bytes(reply any, err error) ([]byte, error) {
+	return redis.Bytes(reply, err)
+}
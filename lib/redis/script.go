@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+)
+
+// Script lua脚本, 通过NewScript注册后用EVALSHA执行, 如果redis中没有缓存该脚本则自动降级为EVAL
+type Script struct {
+	keyCount int
+	src      string
+	hash     string
+}
+
+var (
+	_scriptsMutex sync.RWMutex
+	_scripts      = make(map[string]*Script) // sha1 => script
+)
+
+// NewScript 创建并注册lua脚本, keyCount为KEYS的数量, 如果小于0则不检查KEYS的数量
+//
+// e,g:
+//
+//	var scriptIncr = redis.NewScript(1, `return redis.call('INCR', KEYS[1])`)
+//	v, err := redis.Int64(scriptIncr.Run(hdsdk.Redis().My(), []any{"counter"}))
+func NewScript(keyCount int, src string) *Script {
+	h := sha1.New()
+	_, _ = h.Write([]byte(src))
+
+	s := &Script{
+		keyCount: keyCount,
+		src:      src,
+		hash:     hex.EncodeToString(h.Sum(nil)),
+	}
+
+	_scriptsMutex.Lock()
+	defer _scriptsMutex.Unlock()
+	if registered, exists := _scripts[s.hash]; exists {
+		return registered
+	}
+	_scripts[s.hash] = s
+	return s
+}
+
+// LoadScripts 将所有已注册的脚本缓存到redis中
+func LoadScripts(client intf.RedisClient) error {
+	_scriptsMutex.RLock()
+	defer _scriptsMutex.RUnlock()
+
+	for _, s := range _scripts {
+		err := s.Load(client)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Hash 脚本的sha1
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+// Load 将脚本缓存到redis中
+func (s *Script) Load(client intf.RedisClient) error {
+	_, err := client.ScriptLoad(s.src)
+	if err != nil {
+		return errors.Wrapf(err, "load script, sha: %s", s.hash)
+	}
+	return nil
+}
+
+// Run 用EVALSHA执行脚本, 如果redis返回NOSCRIPT则用EVAL重新执行, EVAL执行后redis会自动缓存该脚本
+func (s *Script) Run(client intf.RedisClient, keys []any, args ...any) (any, error) {
+	if s.keyCount >= 0 && len(keys) != s.keyCount {
+		return nil, errors.Errorf("invalid number of script keys, expected: %d, got: %d", s.keyCount, len(keys))
+	}
+
+	reply, err := client.EvalSha(s.hash, keys, args)
+	if err != nil && isNoScriptError(err) {
+		return client.Eval(s.src, keys, args)
+	}
+	return reply, err
+}
+
+func isNoScriptError(err error) bool {
+	return strings.HasPrefix(err.Error(), "NOSCRIPT")
+}
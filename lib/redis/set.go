@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+)
+
+// Set 成员类型为T的redis集合, 成员以编码后的字符串比较, 所以相同的值必须编码出相同的结果
+type Set[T any] struct {
+	client intf.RedisClient
+	codec  Codec
+	key    string
+}
+
+// NewSet 创建类型化的集合, 缺省使用JSONCodec
+func NewSet[T any](client intf.RedisClient, key string, codecs ...Codec) *Set[T] {
+	return &Set[T]{
+		client: client,
+		codec:  getCodec(codecs...),
+		key:    key,
+	}
+}
+
+// Add 添加成员
+func (s *Set[T]) Add(members ...T) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	encoded, err := encodeAll(s.codec, members)
+	if err != nil {
+		return err
+	}
+	return s.client.SAdd(s.key, encoded)
+}
+
+// Remove 删除成员
+func (s *Set[T]) Remove(members ...T) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	encoded, err := encodeAll(s.codec, members)
+	if err != nil {
+		return err
+	}
+	return s.client.SRem(s.key, encoded)
+}
+
+// Contains 检查成员是否存在
+func (s *Set[T]) Contains(member T) (bool, error) {
+	encoded, err := encode(s.codec, member)
+	if err != nil {
+		return false, err
+	}
+	return s.client.SIsMember(s.key, encoded)
+}
+
+// Members 获取所有成员
+func (s *Set[T]) Members() ([]T, error) {
+	items, err := s.client.SMembers(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](s.codec, items)
+}
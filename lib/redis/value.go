@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+)
+
+// GetWith 获取key的值并用codec解码, key不存在时found为false且err为nil
+func GetWith[T any](client intf.RedisClient, codec Codec, key string) (value T, found bool, err error) {
+	data, err := client.Get(key)
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return value, false, nil
+		}
+		return value, false, err
+	}
+
+	value, err = Decode[T](codec, data)
+	if err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+// SetWith 用codec编码后设置key的值, 如果指定了expire则同时设置过期时间(单位为秒)
+func SetWith[T any](client intf.RedisClient, codec Codec, key string, value T, expire ...int) error {
+	s, err := encode(codec, value)
+	if err != nil {
+		return err
+	}
+
+	if len(expire) > 0 && expire[0] > 0 {
+		return client.SetEx(key, s, expire[0])
+	}
+	return client.Set(key, s)
+}
+
+// GetJSON 获取key的值并做JSON解码, key不存在时found为false且err为nil
+func GetJSON[T any](client intf.RedisClient, key string) (T, bool, error) {
+	return GetWith[T](client, JSONCodec, key)
+}
+
+// SetJSON JSON编码后设置key的值, 如果指定了expire则同时设置过期时间(单位为秒)
+func SetJSON[T any](client intf.RedisClient, key string, value T, expire ...int) error {
+	return SetWith(client, JSONCodec, key, value, expire...)
+}
@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/protobuf"
+	"github.com/pkg/errors"
+)
+
+// SortedSet 成员类型为T的redis有序集合, 成员以编码后的字符串比较, 所以相同的值必须编码出相同的结果
+type SortedSet[T any] struct {
+	client intf.RedisClient
+	codec  Codec
+	key    string
+}
+
+// NewSortedSet 创建类型化的有序集合, 缺省使用JSONCodec
+func NewSortedSet[T any](client intf.RedisClient, key string, codecs ...Codec) *SortedSet[T] {
+	return &SortedSet[T]{
+		client: client,
+		codec:  getCodec(codecs...),
+		key:    key,
+	}
+}
+
+// Add 添加成员
+func (z *SortedSet[T]) Add(member T, score float64) error {
+	encoded, err := encode(z.codec, member)
+	if err != nil {
+		return err
+	}
+	return z.client.ZAdd(z.key, score, encoded)
+}
+
+// IncrBy 增加成员的分数, 返回增加后的分数
+func (z *SortedSet[T]) IncrBy(member T, increment float64) (float64, error) {
+	encoded, err := encode(z.codec, member)
+	if err != nil {
+		return 0, err
+	}
+	return z.client.ZIncrBy(z.key, increment, encoded)
+}
+
+// Score 获取成员的分数, 成员不存在时found为false且err为nil
+func (z *SortedSet[T]) Score(member T) (score float64, found bool, err error) {
+	encoded, err := encode(z.codec, member)
+	if err != nil {
+		return 0, false, err
+	}
+
+	score, err = z.client.ZScore(z.key, encoded)
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+// Range 按索引获取成员
+func (z *SortedSet[T]) Range(start, stop int64) ([]T, error) {
+	items, err := z.client.ZRange(z.key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](z.codec, items)
+}
+
+// RevRange 按索引获取成员, 分数从高到低排列
+func (z *SortedSet[T]) RevRange(start, stop int64) ([]T, error) {
+	items, err := z.client.ZRevRange(z.key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](z.codec, items)
+}
+
+// Rank 获取成员按分数从低到高的排名, 成员不存在时found为false且err为nil
+func (z *SortedSet[T]) Rank(member T) (rank int64, found bool, err error) {
+	encoded, err := encode(z.codec, member)
+	if err != nil {
+		return 0, false, err
+	}
+
+	rank, err = z.client.ZRank(z.key, encoded)
+	if err != nil {
+		if errors.Is(err, ErrNil) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return rank, true, nil
+}
+
+// Count 获取分数在min和max之间的成员数量
+func (z *SortedSet[T]) Count(min, max any) (int64, error) {
+	return z.client.ZCount(z.key, min, max)
+}
+
+// RangeByScore 按分数获取成员
+func (z *SortedSet[T]) RangeByScore(min, max any, list *protobuf.ListParam) ([]T, error) {
+	items, err := z.client.ZRangeByScore(z.key, min, max, false, list)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAll[T](z.codec, items)
+}
+
+// Remove 删除成员
+func (z *SortedSet[T]) Remove(members ...T) (int64, error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	encoded, err := encodeAll(z.codec, members)
+	if err != nil {
+		return 0, err
+	}
+
+	args := make([]any, len(encoded))
+	for i, v := range encoded {
+		args[i] = v
+	}
+	return z.client.ZRem(z.key, args...)
+}
+
+// Card 获取成员数量
+func (z *SortedSet[T]) Card() (int, error) {
+	return z.client.ZCard(z.key)
+}
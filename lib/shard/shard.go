@@ -0,0 +1,186 @@
+package shard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/tenant"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// Config 租户路由配置, Mapping中指定的租户优先, 其他租户按Strategy路由, 都没有匹配时使用Default
+type Config struct {
+	Strategy string            `mapstructure:"strategy" json:"strategy"` // map(缺省), hash, range
+	Default  string            `mapstructure:"default" json:"default"`   // 没有匹配时使用的数据源, 为空时使用缺省数据源
+	Mapping  map[string]string `mapstructure:"mapping" json:"mapping"`   // 租户id=>数据源
+	Shards   []string          `mapstructure:"shards" json:"shards"`     // hash策略的数据源, 按租户id取模
+	Ranges   []*RangeConfig    `mapstructure:"ranges" json:"ranges"`     // range策略的租户id区间
+	// 配置了ConfigKey时从dapr配置中心ConfigStore中读取并热更新路由规则, 见Watch
+	ConfigStore string `mapstructure:"config_store" json:"-"`
+	ConfigKey   string `mapstructure:"config_key" json:"-"`
+}
+
+// RangeConfig 租户id在[Min, Max)区间内的使用数据源Name, Max为0表示没有上限
+type RangeConfig struct {
+	Min  int64  `mapstructure:"min" json:"min"`
+	Max  int64  `mapstructure:"max" json:"max"`
+	Name string `mapstructure:"name" json:"name"`
+}
+
+type routerImpl struct {
+	names map[string]struct{} // 可以路由到的数据源
+	table atomic.Pointer[routeTable]
+}
+
+// routeTable 解析后的路由规则, 热更新时整体替换
+type routeTable struct {
+	strategy string
+	fallback string
+	mapping  map[int64]string
+	shards   []string
+	ranges   []*RangeConfig
+}
+
+const (
+	StrategyMap   = "map"
+	StrategyHash  = "hash"
+	StrategyRange = "range"
+)
+
+// New 创建租户路由, names为可以路由到的数据源名字, e,g: sdk.mysql.items中配置的name
+func New(c *Config, names []string) (intf.ShardRouter, error) {
+	r := &routerImpl{
+		names: make(map[string]struct{}, len(names)),
+	}
+	for _, name := range names {
+		r.names[name] = struct{}{}
+	}
+
+	if err := r.update(c); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RouteContext 从ctx中获取租户id并路由, router为nil或者ctx中没有租户id时返回空
+func RouteContext(ctx context.Context, router intf.ShardRouter) string {
+	if router == nil {
+		return ""
+	}
+
+	tid := tenant.FromContext(ctx)
+	if tid == 0 {
+		return ""
+	}
+	return router.Route(tid)
+}
+
+func (r *routerImpl) Route(tenantId int64) string {
+	t := r.table.Load()
+	if name, ok := t.mapping[tenantId]; ok {
+		return name
+	}
+
+	switch t.strategy {
+	case StrategyHash:
+		return t.shards[uint64(tenantId)%uint64(len(t.shards))]
+	case StrategyRange:
+		// ranges按Min升序排列, 找到最后一个Min<=tenantId的区间
+		i := sort.Search(len(t.ranges), func(i int) bool { return t.ranges[i].Min > tenantId }) - 1
+		if i >= 0 && (t.ranges[i].Max == 0 || tenantId < t.ranges[i].Max) {
+			return t.ranges[i].Name
+		}
+	}
+	return t.fallback
+}
+
+func (r *routerImpl) Reload(data []byte) error {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return errors.Wrap(err, "unmarshal shard config")
+	}
+	return r.update(&c)
+}
+
+func (r *routerImpl) update(c *Config) error {
+	if c == nil {
+		return errors.New("empty shard config")
+	}
+
+	t := &routeTable{
+		strategy: c.Strategy,
+		fallback: c.Default,
+		mapping:  make(map[int64]string, len(c.Mapping)),
+		shards:   c.Shards,
+		ranges:   make([]*RangeConfig, len(c.Ranges)),
+	}
+	if t.strategy == "" {
+		t.strategy = StrategyMap
+	}
+
+	if err := r.validateName(t.fallback, true); err != nil {
+		return err
+	}
+
+	for k, name := range c.Mapping {
+		tid, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid tenant id in shard mapping, tenant: %s", k)
+		}
+
+		if err = r.validateName(name, false); err != nil {
+			return err
+		}
+		t.mapping[tid] = name
+	}
+
+	switch t.strategy {
+	case StrategyMap:
+	case StrategyHash:
+		if len(t.shards) == 0 {
+			return errors.New("hash strategy requires shards")
+		}
+
+		for _, name := range t.shards {
+			if err := r.validateName(name, false); err != nil {
+				return err
+			}
+		}
+	case StrategyRange:
+		copy(t.ranges, c.Ranges)
+		sort.Slice(t.ranges, func(i, j int) bool { return t.ranges[i].Min < t.ranges[j].Min })
+		for i, rc := range t.ranges {
+			if rc.Max != 0 && rc.Max <= rc.Min {
+				return fmt.Errorf("invalid shard range, min: %d, max: %d", rc.Min, rc.Max)
+			}
+
+			if i > 0 && (t.ranges[i-1].Max == 0 || t.ranges[i-1].Max > rc.Min) {
+				return fmt.Errorf("overlapped shard range, min: %d", rc.Min)
+			}
+
+			if err := r.validateName(rc.Name, false); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported shard strategy: %s", t.strategy)
+	}
+
+	r.table.Store(t)
+	return nil
+}
+
+func (r *routerImpl) validateName(name string, allowEmpty bool) error {
+	if name == "" && allowEmpty {
+		return nil
+	}
+
+	if _, ok := r.names[name]; !ok {
+		return fmt.Errorf("shard not found, name: %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package shard
+
+import (
+	"context"
+	"github.com/dapr/go-sdk/client"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/dapr"
+	"github.com/hdget/hdutils/convert"
+	"github.com/pkg/errors"
+)
+
+// Watch 配置了ConfigKey时从dapr配置中心加载路由规则, 并订阅配置变化调用router.Reload热更新, 配置值为json格式的路由配置
+// 没有配置ConfigKey时不做任何事情, 路由规则只能通过Reload手动更新
+func Watch(router intf.ShardRouter, c *Config, logger intf.LoggerProvider) error {
+	if router == nil || c == nil || c.ConfigKey == "" {
+		return nil
+	}
+
+	if c.ConfigStore == "" {
+		return errors.New("config store required to watch shard config")
+	}
+
+	items, err := dapr.Api().GetConfigurationItems(c.ConfigStore, []string{c.ConfigKey})
+	if err != nil {
+		return errors.Wrap(err, "get shard config")
+	}
+
+	if item, ok := items[c.ConfigKey]; ok && item.Value != "" {
+		if err = router.Reload(convert.StringToBytes(item.Value)); err != nil {
+			return errors.Wrapf(err, "reload shard config, key: %s", c.ConfigKey)
+		}
+	}
+
+	subscriberId, err := dapr.Api().SubscribeConfigurationItems(context.Background(), c.ConfigStore, []string{c.ConfigKey}, func(id string, items map[string]*client.ConfigurationItem) {
+		item, ok := items[c.ConfigKey]
+		if !ok || item.Value == "" {
+			return
+		}
+
+		// 更新失败时保留原来的路由规则
+		if err := router.Reload(convert.StringToBytes(item.Value)); err != nil {
+			logger.Error("reload shard config", "key", c.ConfigKey, "value", item.Value, "err", err)
+			return
+		}
+		logger.Info("shard config reloaded", "key", c.ConfigKey)
+	})
+	if err != nil {
+		return errors.Wrap(err, "subscribe shard config changes")
+	}
+
+	logger.Debug("subscribe shard config changes", "key", c.ConfigKey, "subscriberId", subscriberId)
+	return nil
+}
@@ -0,0 +1,67 @@
+package sqlboiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect SQL方言, 决定IfNull, JsonValue等helper生成的SQL
+type Dialect int
+
+const (
+	DialectMysql Dialect = iota
+	DialectPostgres
+)
+
+// 包级别的helper, e,g: IfNull, JsonValue总是使用MySQL方言, 其他数据库使用指定方言的版本, e,g: sqlboiler.DialectPostgres.IfNull(...)
+// 不提供修改包级别方言的方法, 避免同一个进程中同时使用MySQL和PostgreSQL时互相影响
+var defaultDialect = DialectMysql
+
+// driverNamer sqlx.DB实现了该接口
+type driverNamer interface {
+	DriverName() string
+}
+
+// DialectOf 根据数据库client的driver获取SQL方言, 无法获取driver时为MySQL
+// e,g: sqlboiler.DialectOf(sdk.Db().My()).IfNull("name", "")
+func DialectOf(client any) Dialect {
+	if v, ok := client.(driverNamer); ok {
+		switch v.DriverName() {
+		case "postgres", "pgx":
+			return DialectPostgres
+		}
+	}
+	return DialectMysql
+}
+
+// Quote 引用标识符, MySQL使用`, PostgreSQL使用"
+func (d Dialect) Quote(identifier string) string {
+	if d == DialectPostgres {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// coalesce 表达式为NULL时返回fallback
+func (d Dialect) coalesce(expr, fallback string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("COALESCE((%s), %s)", expr, fallback)
+	}
+	return fmt.Sprintf("IFNULL((%s), %s)", expr, fallback)
+}
+
+// jsonText 以文本形式提取json字段
+func (d Dialect) jsonText(jsonColumn, jsonKey string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("(%s->>'%s')", jsonColumn, jsonKey)
+	}
+	return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s'))", jsonColumn, jsonKey)
+}
+
+// jsonNumber 以数值形式提取json字段
+func (d Dialect) jsonNumber(jsonColumn, jsonKey string) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("(%s->>'%s')::numeric", jsonColumn, jsonKey)
+	}
+	return fmt.Sprintf("JSON_EXTRACT(%s, '$.%s')", jsonColumn, jsonKey)
+}
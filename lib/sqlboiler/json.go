@@ -6,29 +6,38 @@ import (
 )
 
 func JsonValue(jsonColumn string, jsonKey string, defaultValue any) qm.QueryMod {
+	return defaultDialect.JsonValue(jsonColumn, jsonKey, defaultValue)
+}
+
+func JsonValueCompare(jsonColumn string, jsonKey string, operator string, compareValue any) qm.QueryMod {
+	return defaultDialect.JsonValueCompare(jsonColumn, jsonKey, operator, compareValue)
+}
+
+// JsonValue 提取json字段的值, MySQL使用JSON_EXTRACT, PostgreSQL使用jsonb的->>操作符
+func (d Dialect) JsonValue(jsonColumn string, jsonKey string, defaultValue any) qm.QueryMod {
 	var template string
 	switch v := defaultValue.(type) {
 	case string:
-		template = fmt.Sprintf("IFNULL(JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s')), '%s') AS %s", jsonColumn, jsonKey, v, jsonKey)
+		template = fmt.Sprintf("%s AS %s", d.coalesce(d.jsonText(jsonColumn, jsonKey), fmt.Sprintf("'%s'", v)), jsonKey)
 	case int8, int, int32, int64:
-		template = fmt.Sprintf("IFNULL(JSON_EXTRACT(%s, '$.%s'), %d) AS %s", jsonColumn, jsonKey, v, jsonKey)
+		template = fmt.Sprintf("%s AS %s", d.coalesce(d.jsonNumber(jsonColumn, jsonKey), fmt.Sprintf("%d", v)), jsonKey)
 	case float32, float64:
-		template = fmt.Sprintf("IFNULL(JSON_EXTRACT(%s, '$.%s'), %f) AS %s", jsonColumn, jsonKey, v, jsonKey)
+		template = fmt.Sprintf("%s AS %s", d.coalesce(d.jsonNumber(jsonColumn, jsonKey), fmt.Sprintf("%f", v)), jsonKey)
 	default:
 		return nil
 	}
 	return qm.Select(template)
 }
 
-func JsonValueCompare(jsonColumn string, jsonKey string, operator string, compareValue any) qm.QueryMod {
+func (d Dialect) JsonValueCompare(jsonColumn string, jsonKey string, operator string, compareValue any) qm.QueryMod {
 	var template string
 	switch v := compareValue.(type) {
 	case string:
-		template = fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '$.%s')) %s '%s'", jsonColumn, jsonKey, operator, v)
+		template = fmt.Sprintf("%s %s '%s'", d.jsonText(jsonColumn, jsonKey), operator, v)
 	case int8, int, int32, int64:
-		template = fmt.Sprintf("JSON_EXTRACT(%s, '$.%s') %s %d", jsonColumn, jsonKey, operator, v)
+		template = fmt.Sprintf("%s %s %d", d.jsonNumber(jsonColumn, jsonKey), operator, v)
 	case float32, float64:
-		template = fmt.Sprintf("JSON_EXTRACT(%s, '$.%s') %s %f", jsonColumn, jsonKey, operator, v)
+		template = fmt.Sprintf("%s %s %f", d.jsonNumber(jsonColumn, jsonKey), operator, v)
 	default:
 		return nil
 	}
@@ -34,6 +34,12 @@ func (q *qmBuilder) Limit(list *protobuf.ListParam) *qmBuilder {
 	return q
 }
 
+// Keyset 游标分页, 见GetKeysetQueryMods
+func (q *qmBuilder) Keyset(ks *pagination.Keyset) *qmBuilder {
+	q.mods = append(q.mods, GetKeysetQueryMods(ks)...)
+	return q
+}
+
 func (q *qmBuilder) Output() []qm.QueryMod {
 	return q.mods
 }
@@ -19,44 +19,63 @@ func GetLimitQueryMods(list *protobuf.ListParam) []qm.QueryMod {
 	return []qm.QueryMod{qm.Offset(int(p.Offset)), qm.Limit(int(p.PageSize))}
 }
 
+// GetKeysetQueryMods 获取游标分页相关QueryMods, 包含游标条件, 排序和Limit, 不能再添加其他OrderBy
+// 查询结果需要调用ks.Paginate去掉多取的数据并生成前后页的游标
+func GetKeysetQueryMods(ks *pagination.Keyset) []qm.QueryMod {
+	mods := make([]qm.QueryMod, 0, 3)
+	if where, args := ks.Where(); where != "" {
+		mods = append(mods, qm.Where(where, args...))
+	}
+	return append(mods, qm.OrderBy(ks.OrderBy()), qm.Limit(int(ks.Limit())))
+}
+
 func IfNull(column string, defaultValue any, args ...string) string {
+	return defaultDialect.IfNull(column, defaultValue, args...)
+}
+
+func IfNullWithColumn(column string, anotherColumn string, args ...string) string {
+	return defaultDialect.IfNullWithColumn(column, anotherColumn, args...)
+}
+
+// IfNull 列值为NULL时使用缺省值, MySQL为IFNULL, PostgreSQL为COALESCE
+func (d Dialect) IfNull(column string, defaultValue any, args ...string) string {
 	alias := column
 	if len(args) > 0 {
 		alias = args[0]
 	}
 
 	if defaultValue == nil {
-		return fmt.Sprintf("IFNULL((%s), '') AS \"%s\"", column, alias)
+		return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, "''"), alias)
 	}
 
 	v := reflectUtils.Indirect(defaultValue)
 
 	switch vv := reflect.ValueOf(v); vv.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return fmt.Sprintf("IFNULL((%s), %d) AS \"%s\"", column, v, alias)
+		return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, fmt.Sprintf("%d", v)), alias)
 	case reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("IFNULL((%s), %.4f) AS \"%s\"", column, v, alias)
+		return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, fmt.Sprintf("%.4f", v)), alias)
 	case reflect.Slice:
 		if vv.Type().Elem().Kind() == reflect.Uint8 {
 			if jsonUtils.IsEmptyJsonObject(vv.Bytes()) {
-				return fmt.Sprintf("IFNULL((%s), '{}') AS \"%s\"", column, alias)
+				return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, "'{}'"), alias)
 			} else if jsonUtils.IsEmptyJsonArray(vv.Bytes()) {
-				return fmt.Sprintf("IFNULL((%s), '[]') AS \"%s\"", column, alias)
+				return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, "'[]'"), alias)
 			} else {
-				return fmt.Sprintf("IFNULL((%s), '%s') AS \"%s\"", column, convert.BytesToString(vv.Bytes()), alias)
+				return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, fmt.Sprintf("'%s'", convert.BytesToString(vv.Bytes()))), alias)
 			}
 		}
 	}
 
-	return fmt.Sprintf("IFNULL((%s), '%v') AS \"%s\"", column, defaultValue, alias)
+	return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, fmt.Sprintf("'%v'", defaultValue)), alias)
 }
 
-func IfNullWithColumn(column string, anotherColumn string, args ...string) string {
+func (d Dialect) IfNullWithColumn(column string, anotherColumn string, args ...string) string {
 	alias := column
 	if len(args) > 0 {
 		alias = args[0]
 	}
-	return fmt.Sprintf("IFNULL((%s), %s) AS \"%s\"", column, anotherColumn, alias)
+	return fmt.Sprintf("%s AS \"%s\"", d.coalesce(column, anotherColumn), alias)
 }
 
 // WithUpdateTime 除了cols中的会更新以外还会更新更新时间字段
@@ -71,7 +90,11 @@ func WithUpdateTime(cols map[string]any, args ...string) map[string]any {
 }
 
 func AsAliasColumn(alias, colName string) string {
-	return fmt.Sprintf("`%s`.`%s` AS \"%s.%s\"", alias, colName, alias, colName)
+	return defaultDialect.AsAliasColumn(alias, colName)
+}
+
+func (d Dialect) AsAliasColumn(alias, colName string) string {
+	return fmt.Sprintf("%s.%s AS \"%s.%s\"", d.Quote(alias), d.Quote(colName), alias, colName)
 }
 
 func GetDB(args ...boil.Executor) boil.Executor {
@@ -82,5 +105,9 @@ func GetDB(args ...boil.Executor) boil.Executor {
 }
 
 func SUM(col string, args ...string) string {
-	return IfNull(fmt.Sprintf("SUM(%s)", col), 0, args...)
+	return defaultDialect.SUM(col, args...)
+}
+
+func (d Dialect) SUM(col string, args ...string) string {
+	return d.IfNull(fmt.Sprintf("SUM(%s)", col), 0, args...)
 }
@@ -0,0 +1,54 @@
+package sqlboiler
+
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2/lib/tenant"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// sqlboiler生成的模型没有查询前的hook, 查询不会像sqltemplate一样自动加上租户条件, 需要:
+// 1. 查询时使用WithTenant或者TenantQueryMods加上租户条件
+// 2. 注册TenantAfterSelect, 查询出其他租户的数据时返回错误, 防止漏加租户条件
+// 3. 注册TenantBeforeInsert, 插入时自动设置租户字段
+
+// TenantQueryMods 生成租户隔离的查询条件, table需要先通过tenant.Register注册, 不需要租户条件时返回空
+// e,g: models.Users(sqlboiler.NewQmBuilder(mods...).Concat(tenantMods).Output()...)
+func TenantQueryMods(ctx context.Context, table string, alias ...string) ([]qm.QueryMod, error) {
+	var as string
+	if len(alias) > 0 {
+		as = alias[0]
+	}
+
+	condition, err := tenant.Condition(ctx, table, as)
+	if err != nil || condition == "" {
+		return nil, err
+	}
+	return []qm.QueryMod{qm.Where(condition)}, nil
+}
+
+// WithTenant 在mods后面加上table的租户条件, 没有注册租户隔离的表原样返回mods
+// e,g: mods, err := sqlboiler.WithTenant(ctx, models.TableNames.Users, qm.Where("status = ?", 1))
+func WithTenant(ctx context.Context, table string, mods ...qm.QueryMod) ([]qm.QueryMod, error) {
+	tenantMods, err := TenantQueryMods(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	return NewQmBuilder(mods...).Concat(tenantMods).Output(), nil
+}
+
+// TenantBeforeInsert 生成插入前自动设置租户字段的hook, 可以注册为sqlboiler模型的BeforeInsertHook和BeforeUpsertHook
+// e,g: models.AddUserHook(boil.BeforeInsertHook, sqlboiler.TenantBeforeInsert[models.User](models.TableNames.Users))
+func TenantBeforeInsert[T any](table string) func(ctx context.Context, exec boil.ContextExecutor, o *T) error {
+	return func(ctx context.Context, exec boil.ContextExecutor, o *T) error {
+		return tenant.FillInsert(ctx, table, o)
+	}
+}
+
+// TenantAfterSelect 生成查询后检查租户字段的hook, 可以注册为sqlboiler模型的AfterSelectHook
+// e,g: models.AddUserHook(boil.AfterSelectHook, sqlboiler.TenantAfterSelect[models.User](models.TableNames.Users))
+func TenantAfterSelect[T any](table string) func(ctx context.Context, exec boil.ContextExecutor, o *T) error {
+	return func(ctx context.Context, exec boil.ContextExecutor, o *T) error {
+		return tenant.Verify(ctx, table, o)
+	}
+}
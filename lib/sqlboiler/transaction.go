@@ -7,6 +7,8 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/boil"
 )
 
+// Transaction
+// Deprecated: 不支持嵌套事务, 请使用db.InTx
 type Transaction struct {
 	Tx         boil.Transactor
 	needCommit bool
@@ -7,6 +7,8 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/boil"
 )
 
+// Transactor
+// Deprecated: 不支持嵌套事务, 请使用db.InTx
 type Transactor interface {
 	Executor() boil.Executor
 	Finalize(err error)
@@ -0,0 +1,47 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdutils/page"
+	"github.com/jmoiron/sqlx"
+)
+
+// dialect 不同数据库的SQL差异, 通过dbClient的driver名字判断
+type dialect struct {
+	bindType   int
+	ansiQuotes bool // 双引号是否引用标识符, MySQL中双引号为字符串
+}
+
+// driverNamer sqlx.DB实现了该接口
+type driverNamer interface {
+	DriverName() string
+}
+
+func getDialect(dbClient intf.DbClient) *dialect {
+	bindType, ansiQuotes := sqlx.QUESTION, false
+	if v, ok := dbClient.(driverNamer); ok {
+		bindType = sqlx.BindType(v.DriverName())
+		if bindType == sqlx.UNKNOWN {
+			bindType = sqlx.QUESTION
+		}
+		ansiQuotes = v.DriverName() != "mysql"
+	}
+	return &dialect{bindType: bindType, ansiQuotes: ansiQuotes}
+}
+
+// limitClause PostgreSQL不支持LIMIT offset, count的写法
+func (d *dialect) limitClause(p page.Pagination) string {
+	if d.bindType == sqlx.DOLLAR {
+		return fmt.Sprintf("LIMIT %d OFFSET %d", p.PageSize, p.Offset)
+	}
+	return p.GetLimitClause()
+}
+
+// rebind 将?占位符转换为driver对应的类型, e,g: PostgreSQL为$1, $2...
+func (d *dialect) rebind(query string) string {
+	if d.bindType == sqlx.QUESTION {
+		return query
+	}
+	return sqlx.Rebind(d.bindType, query)
+}
@@ -0,0 +1,260 @@
+package sqltemplate
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // 关键字, 标识符, 数字
+	tokenQuoted                  // 引号引用的标识符, e,g: `user`, "user"
+	tokenString                  // 字符串
+	tokenPunct                   // 其他字符, e,g: ( ) , . ?
+)
+
+// token SQL中的词法单元, start和end为在模板中的位置
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// tableRef 模板中FROM/JOIN后面的表, start和end为限定表名在模板中的位置, e,g: db.`user`
+type tableRef struct {
+	table     string // 去掉schema和引号的表名
+	qualified string // 模板中的限定表名
+	last      string // 模板中限定表名的最后一部分
+	alias     string
+	start     int
+	end       int
+	toWhere   bool  // 租户条件加到最外层的WHERE中, 否则改写为派生表
+	tokens    []int // 表名和别名对应的token
+}
+
+// scope 每层括号中FROM子句的状态
+type scope struct {
+	inFrom    bool // 在FROM子句中, 逗号后面是另一张表
+	outerJoin bool // 遇到了LEFT/RIGHT/FULL, 下一个JOIN是外连接
+}
+
+var (
+	// 表名后面可能紧跟的关键字, 不能作为别名
+	aliasKeywords = map[string]struct{}{
+		"WHERE": {}, "INNER": {}, "LEFT": {}, "RIGHT": {}, "OUTER": {}, "CROSS": {}, "JOIN": {}, "ON": {},
+		"GROUP": {}, "ORDER": {}, "LIMIT": {}, "HAVING": {}, "UNION": {}, "USING": {}, "FOR": {}, "NATURAL": {},
+		"FULL": {}, "EXCEPT": {}, "INTERSECT": {}, "WINDOW": {}, "OFFSET": {}, "STRAIGHT_JOIN": {},
+		"USE": {}, "FORCE": {}, "IGNORE": {}, "RETURNING": {},
+	}
+	// FROM子句结束的关键字
+	endFromKeywords = map[string]struct{}{
+		"WHERE": {}, "GROUP": {}, "ORDER": {}, "LIMIT": {}, "HAVING": {}, "UNION": {}, "EXCEPT": {},
+		"INTERSECT": {}, "WINDOW": {}, "FOR": {}, "OFFSET": {}, "RETURNING": {},
+	}
+	// 最外层有这些关键字时, 最外层WHERE中的条件不能覆盖所有的表
+	setOperatorKeywords = map[string]struct{}{
+		"UNION": {}, "EXCEPT": {}, "INTERSECT": {}, "RIGHT": {}, "FULL": {},
+	}
+)
+
+// tokenize 将模板拆分为token, 忽略空白和注释
+func tokenize(template string, ansiQuotes bool) []*token {
+	var tokens []*token
+	for i := 0; i < len(template); {
+		c := template[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '-' && strings.HasPrefix(template[i:], "--"):
+			end := strings.IndexByte(template[i:], '\n')
+			if end < 0 {
+				i = len(template)
+			} else {
+				i += end + 1
+			}
+		case c == '/' && strings.HasPrefix(template[i:], "/*"):
+			end := strings.Index(template[i+2:], "*/")
+			if end < 0 {
+				i = len(template)
+			} else {
+				i += end + 4
+			}
+		case c == '`' || (c == '"' && ansiQuotes):
+			end := scanQuoted(template, i, false)
+			tokens = append(tokens, &token{kind: tokenQuoted, text: template[i:end], start: i, end: end})
+			i = end
+		case c == '\'' || c == '"':
+			// MySQL的字符串中反斜杠为转义字符, PostgreSQL和SQLite没有
+			end := scanQuoted(template, i, !ansiQuotes)
+			tokens = append(tokens, &token{kind: tokenString, text: template[i:end], start: i, end: end})
+			i = end
+		case isWordChar(c):
+			end := i + 1
+			for end < len(template) && isWordChar(template[end]) {
+				end++
+			}
+			tokens = append(tokens, &token{kind: tokenWord, text: template[i:end], start: i, end: end})
+			i = end
+		default:
+			tokens = append(tokens, &token{kind: tokenPunct, text: template[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens
+}
+
+// parseTableRefs 找出模板中所有FROM/JOIN后面的表, 包括逗号分隔的表和子查询中的表
+// 只有最外层FROM和内连接的表, 并且最外层没有UNION和右连接时, 租户条件才能加到最外层的WHERE中
+func parseTableRefs(template string, tokens []*token) []*tableRef {
+	toWhereAllowed := true
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case depth == 0 && t.kind == tokenWord:
+			if _, ok := setOperatorKeywords[strings.ToUpper(t.text)]; ok {
+				toWhereAllowed = false
+			}
+		}
+	}
+
+	var refs []*tableRef
+	scopes := []*scope{{}}
+	for i := 0; i < len(tokens); i++ {
+		t, cur := tokens[i], scopes[len(scopes)-1]
+		outer := false
+		switch {
+		case t.is("("):
+			scopes = append(scopes, &scope{})
+			continue
+		case t.is(")"):
+			if len(scopes) > 1 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			continue
+		case t.is(","):
+			if !cur.inFrom {
+				continue
+			}
+		case t.kind == tokenWord:
+			switch keyword := strings.ToUpper(t.text); keyword {
+			case "FROM":
+				cur.inFrom = true
+			case "JOIN", "STRAIGHT_JOIN":
+				cur.inFrom = true
+				outer, cur.outerJoin = cur.outerJoin, false
+			case "LEFT", "RIGHT", "FULL":
+				cur.outerJoin = true
+				continue
+			default:
+				if _, ok := endFromKeywords[keyword]; ok {
+					cur.inFrom = false
+				}
+				continue
+			}
+		default:
+			continue
+		}
+
+		ref, last := parseTableRef(template, tokens, i+1)
+		if ref == nil {
+			continue
+		}
+		ref.toWhere = toWhereAllowed && len(scopes) == 1 && !outer
+		refs = append(refs, ref)
+		i = last
+	}
+	return refs
+}
+
+// parseTableRef 解析pos开始的限定表名和别名, 后面不是表名时返回nil, e,g: 子查询
+func parseTableRef(template string, tokens []*token, pos int) (*tableRef, int) {
+	if pos >= len(tokens) || !tokens[pos].isIdentifier() {
+		return nil, pos
+	}
+
+	ref := &tableRef{start: tokens[pos].start, tokens: []int{pos}}
+	last := pos
+	for last+2 < len(tokens) && tokens[last+1].is(".") && tokens[last+2].isIdentifier() {
+		last += 2
+		ref.tokens = append(ref.tokens, last)
+	}
+	ref.end = tokens[last].end
+	ref.qualified = template[ref.start:ref.end]
+	ref.last = tokens[last].text
+	ref.table = tokens[last].name()
+
+	next := last + 1
+	if next < len(tokens) && tokens[next].kind == tokenWord && strings.EqualFold(tokens[next].text, "AS") {
+		next++
+	}
+	if next < len(tokens) && tokens[next].isIdentifier() {
+		ref.alias = tokens[next].text
+		ref.tokens = append(ref.tokens, next)
+		last = next
+	}
+	return ref, last
+}
+
+func (t *token) is(punct string) bool {
+	return t.kind == tokenPunct && t.text == punct
+}
+
+// isIdentifier 引号引用的标识符, 或者不是关键字的单词
+func (t *token) isIdentifier() bool {
+	switch t.kind {
+	case tokenQuoted:
+		return true
+	case tokenWord:
+		if _, isKeyword := aliasKeywords[strings.ToUpper(t.text)]; isKeyword {
+			return false
+		}
+		return !isDigit(t.text[0])
+	}
+	return false
+}
+
+// name 去掉引号后的标识符
+func (t *token) name() string {
+	if t.kind != tokenQuoted {
+		return t.text
+	}
+	quote := t.text[:1]
+	return strings.ReplaceAll(strings.TrimSuffix(t.text[1:], quote), quote+quote, quote)
+}
+
+// scanQuoted 返回引号结束后的位置, 两个连续的引号表示引号本身, backslash为true时反斜杠转义下一个字符
+func scanQuoted(s string, start int, backslash bool) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
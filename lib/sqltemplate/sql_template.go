@@ -1,10 +1,12 @@
 package sqltemplate
 
 import (
+	"context"
 	"fmt"
 	"github.com/hdget/hdsdk/v2/intf"
 	"github.com/hdget/hdsdk/v2/lib/pagination"
 	"github.com/hdget/hdsdk/v2/protobuf"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"strings"
@@ -23,15 +25,20 @@ type SqlTemplate interface {
 
 	Where(condition string, args ...any)
 	Generate() (string, []any, error)
-	Get(v any) error
+	GenerateContext(ctx context.Context) (string, []any, error) // ctx用于生成租户条件
+	Get(v any) error                                            // 没有ctx的Get/Select/Count不能访问租户隔离的表, 返回tenant.ErrTenantMissing
 	Select(v any) error
 	Count() (int64, error)
+	GetContext(ctx context.Context, v any) error
+	SelectContext(ctx context.Context, v any) error
+	CountContext(ctx context.Context) (int64, error)
 	ExportConditions() ([]string, []any) // 导出where条件
 	ImportConditions([]string, []any)    // 导入where条件
 }
 
 type sqlTemplateImpl struct {
 	dbClient    intf.DbClient
+	dialect     *dialect
 	concrete    SqlTemplate
 	template    string
 	wheres      []string
@@ -54,14 +61,14 @@ type query struct {
 }
 
 const (
-	defaultNextClause  = "LIMIT 10"
-	defaultLimitClause = "LIMIT 0, 10"
+	defaultNextClause = "LIMIT 10"
 )
 
 func New(dbClient intf.DbClient) SqlTemplate {
 	me := &query{
 		sqlTemplateImpl: &sqlTemplateImpl{
 			dbClient: dbClient,
+			dialect:  getDialect(dbClient),
 			wheres:   make([]string, 0),
 			args:     make([]any, 0),
 		},
@@ -74,6 +81,7 @@ func NewJoinSubQuery(dbClient intf.DbClient, template, alias, on string) SqlTemp
 	me := &joinSubQuery{
 		sqlTemplateImpl: &sqlTemplateImpl{
 			dbClient: dbClient,
+			dialect:  getDialect(dbClient),
 			template: template,
 			wheres:   make([]string, 0),
 			args:     make([]any, 0),
@@ -94,13 +102,17 @@ func (q *query) JoinSubQuery(tpl SqlTemplate) SqlTemplate {
 	return q
 }
 
-// Generate 最终生成SQL语句
-func (q *query) Generate() (string, []any, error) {
-	parts := []string{q.template}
+// GenerateContext 最终生成SQL语句
+func (q *query) GenerateContext(ctx context.Context) (string, []any, error) {
+	template, whereClause, err := q.applyTenant(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	parts := []string{template}
 
 	// join子查询
 	for _, sq := range q.joinSubQuerys {
-		subQuerySql, subQueryArgs, err := sq.Generate()
+		subQuerySql, subQueryArgs, err := sq.GenerateContext(ctx)
 		if err != nil {
 			return "", nil, err
 		}
@@ -109,7 +121,6 @@ func (q *query) Generate() (string, []any, error) {
 		q.InsertArgs(subQueryArgs...)
 	}
 
-	whereClause := q.getWhereClause()
 	if whereClause != "" {
 		parts = append(parts, whereClause)
 	}
@@ -126,15 +137,19 @@ func (q *query) Generate() (string, []any, error) {
 		parts = append(parts, q.limitClause)
 	}
 
-	return q.process(strings.Join(parts, " "))
+	// 只有最外层的查询需要转换占位符, 否则子查询的占位符序号会和外层冲突
+	return q.process(strings.Join(parts, " "), true)
 }
 
 /* joinSubQuery */
 
-func (sq *joinSubQuery) Generate() (string, []any, error) {
-	parts := []string{sq.template}
+func (sq *joinSubQuery) GenerateContext(ctx context.Context) (string, []any, error) {
+	template, whereClause, err := sq.applyTenant(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	parts := []string{template}
 
-	whereClause := sq.getWhereClause()
 	if whereClause != "" {
 		parts = append(parts, whereClause)
 	}
@@ -151,7 +166,7 @@ func (sq *joinSubQuery) Generate() (string, []any, error) {
 		parts = append(parts, sq.limitClause)
 	}
 
-	return sq.process(strings.Join(parts, " "))
+	return sq.process(strings.Join(parts, " "), false)
 }
 
 /* sqlTemplateImpl */
@@ -175,11 +190,7 @@ func (b *sqlTemplateImpl) With(template string) SqlTemplate {
 }
 
 func (b *sqlTemplateImpl) Limit(listParam *protobuf.ListParam) SqlTemplate {
-	if listParam != nil {
-		b.limitClause = pagination.New(listParam).GetLimitClause()
-		return b.concrete
-	}
-	b.limitClause = defaultLimitClause
+	b.limitClause = b.dialect.limitClause(pagination.New(listParam))
 	return b.concrete
 }
 
@@ -226,72 +237,137 @@ func (b *sqlTemplateImpl) JoinSubQuery(tpl SqlTemplate) SqlTemplate {
 	return b.concrete.JoinSubQuery(tpl)
 }
 
+// Generate 没有ctx无法判断租户, 模板中有租户隔离的表时返回tenant.ErrTenantMissing, 需要使用GenerateContext
 func (b *sqlTemplateImpl) Generate() (string, []any, error) {
-	return b.concrete.Generate()
+	return b.concrete.GenerateContext(nil)
 }
 
+// ToSql 同Generate
 func (b *sqlTemplateImpl) ToSql() (string, []any, error) {
-	return b.concrete.Generate()
+	return b.concrete.GenerateContext(nil)
+}
+
+// ToSqlContext 同GenerateContext, 实现intf.ContextSqlizer, 作为DbBuilder的sqlizer时使用XGet等方法的ctx生成租户条件
+func (b *sqlTemplateImpl) ToSqlContext(ctx context.Context) (string, []any, error) {
+	return b.concrete.GenerateContext(ctx)
 }
 
+// Get 没有ctx无法判断租户, 同Generate, 模板中有租户隔离的表时返回tenant.ErrTenantMissing, 需要使用GetContext
 func (b *sqlTemplateImpl) Get(v any) error {
-	xquery, xargs, err := b.concrete.Generate()
+	return b.get(nil, v)
+}
+
+// Select 同Get, 模板中有租户隔离的表时需要使用SelectContext
+func (b *sqlTemplateImpl) Select(v any) error {
+	return b.selectRows(nil, v)
+}
+
+// Count 同Get, 模板中有租户隔离的表时需要使用CountContext
+func (b *sqlTemplateImpl) Count() (int64, error) {
+	return b.count(nil)
+}
+
+func (b *sqlTemplateImpl) GetContext(ctx context.Context, v any) error {
+	return b.get(ctx, v)
+}
+
+func (b *sqlTemplateImpl) SelectContext(ctx context.Context, v any) error {
+	return b.selectRows(ctx, v)
+}
+
+func (b *sqlTemplateImpl) CountContext(ctx context.Context) (int64, error) {
+	return b.count(ctx)
+}
+
+// get ctx为nil时不能生成租户条件, 只用于没有ctx的Get, 执行时使用context.Background()
+func (b *sqlTemplateImpl) get(ctx context.Context, v any) error {
+	xquery, xargs, err := b.concrete.GenerateContext(ctx)
 	if err != nil {
 		return errors.Wrap(err, "generate sql")
 	}
 
-	err = b.dbClient.Get(v, xquery, xargs...)
+	ctx = orBackground(ctx)
+	err = db.Executor(ctx, b.dbClient).GetContext(ctx, v, xquery, xargs...)
 	if err != nil {
 		return errors.Wrap(err, "db get")
 	}
 	return nil
 }
 
-func (b *sqlTemplateImpl) Select(v any) error {
-	xquery, xargs, err := b.concrete.Generate()
+func (b *sqlTemplateImpl) selectRows(ctx context.Context, v any) error {
+	xquery, xargs, err := b.concrete.GenerateContext(ctx)
 	if err != nil {
 		return errors.Wrap(err, "generate sql")
 	}
 
-	err = b.dbClient.Select(v, xquery, xargs...)
+	ctx = orBackground(ctx)
+	err = db.Executor(ctx, b.dbClient).SelectContext(ctx, v, xquery, xargs...)
 	if err != nil {
 		return errors.Wrap(err, "db select")
 	}
 	return nil
 }
 
-func (b *sqlTemplateImpl) Count() (int64, error) {
+func (b *sqlTemplateImpl) count(ctx context.Context) (int64, error) {
 	// 获取total
-	xquery, xargs, err := b.concrete.Generate()
+	xquery, xargs, err := b.concrete.GenerateContext(ctx)
 	if err != nil {
 		return 0, errors.Wrap(err, "generate sql")
 	}
 
 	var total int64
-	err = b.dbClient.Get(&total, xquery, xargs...)
+	ctx = orBackground(ctx)
+	err = db.Executor(ctx, b.dbClient).GetContext(ctx, &total, xquery, xargs...)
 	if err != nil {
 		return 0, errors.Wrap(err, "db count")
 	}
 	return total, nil
 }
 
-func (b *sqlTemplateImpl) getWhereClause() string {
-	if len(b.wheres) == 0 {
+// applyTenant 模板中FROM/JOIN的表如果注册了租户隔离, 会自动加上租户条件, 返回改写后的模板和where子句
+func (b *sqlTemplateImpl) applyTenant(ctx context.Context) (string, string, error) {
+	template, tenantConditions, err := b.dialect.applyTenant(ctx, b.template)
+	if err != nil {
+		return "", "", err
+	}
+	return template, b.getWhereClause(tenantConditions), nil
+}
+
+// getWhereClause 合并Where添加的条件和租户条件
+func (b *sqlTemplateImpl) getWhereClause(tenantConditions []string) string {
+
+	wheres := b.wheres
+	if len(tenantConditions) > 0 {
+		wheres = append(append(make([]string, 0, len(b.wheres)+len(tenantConditions)), b.wheres...), tenantConditions...)
+	}
+
+	if len(wheres) == 0 {
 		return ""
 	}
-	return fmt.Sprintf("WHERE %s", strings.Join(b.wheres, " AND "))
+	return fmt.Sprintf("WHERE %s", strings.Join(wheres, " AND "))
 }
 
-// process 后期处理，现在暂时只处理SQL中的IN关键字
-func (b *sqlTemplateImpl) process(query string) (string, []any, error) {
+// process 后期处理, 展开SQL中IN关键字的参数, rebind为true时将?占位符转换为数据库driver对应的类型
+func (b *sqlTemplateImpl) process(query string, rebind bool) (string, []any, error) {
+	args := b.args
 	// 发现如果where里面有IN, 需要特殊处理
 	if strings.Contains(strings.ToUpper(query), " IN ") {
-		newQuery, newArgs, err := sqlx.In(query, b.args...)
+		var err error
+		query, args, err = sqlx.In(query, b.args...)
 		if err != nil {
-			return "", nil, errors.Wrap(err, "sqlboiler-sqlite3-sqlx.In")
+			return "", nil, errors.Wrap(err, "sqlx.In")
 		}
-		newQuery = b.dbClient.Rebind(newQuery)
-		return newQuery, newArgs, nil
 	}
-	return query, b.args, nil
+
+	if rebind {
+		query = b.dialect.rebind(query)
+	}
+	return query, args, nil
+}
+
+func orBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
 }
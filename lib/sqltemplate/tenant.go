@@ -0,0 +1,104 @@
+package sqltemplate
+
+import (
+	"context"
+	"fmt"
+	"github.com/hdget/hdsdk/v2/lib/tenant"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// applyTenant 为模板中注册了租户隔离的表加上租户条件, 返回改写后的模板和需要加到WHERE中的租户条件
+// 最外层FROM和内连接的表的租户条件加到WHERE中, 外连接中可能为NULL的表和子查询中的表改写为带租户条件的派生表,
+// 等同于把租户条件放到ON中或者子查询中, 避免WHERE中的条件把外连接变成内连接或者引用子查询中的表
+// 改写后模板中仍有没有识别出来的租户隔离的表时返回错误, 避免漏加租户条件
+func (d *dialect) applyTenant(ctx context.Context, template string) (string, []string, error) {
+	tokens := tokenize(template, d.ansiQuotes)
+	refs := parseTableRefs(template, tokens)
+
+	var (
+		conditions []string
+		sb         strings.Builder
+		last       int
+	)
+	handled := make(map[int]struct{})
+	for _, ref := range refs {
+		for _, index := range ref.tokens {
+			handled[index] = struct{}{}
+		}
+
+		if ref.toWhere {
+			alias := ref.alias
+			if alias == "" {
+				alias = ref.qualified
+			}
+
+			condition, err := tenant.Condition(ctx, ref.table, alias)
+			if err != nil {
+				return "", nil, err
+			}
+
+			if condition != "" {
+				conditions = append(conditions, condition)
+			}
+			continue
+		}
+
+		// 派生表中只有一张表, 租户条件使用模板中的限定表名
+		condition, err := tenant.Condition(ctx, ref.table, ref.qualified)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if condition == "" {
+			continue
+		}
+
+		derived := fmt.Sprintf("(SELECT * FROM %s WHERE %s)", ref.qualified, condition)
+		if ref.alias == "" {
+			derived += " AS " + ref.last
+		}
+		sb.WriteString(template[last:ref.start])
+		sb.WriteString(derived)
+		last = ref.end
+	}
+
+	if err := checkUnhandled(ctx, tokens, handled); err != nil {
+		return "", nil, err
+	}
+
+	if last == 0 {
+		return template, conditions, nil
+	}
+	sb.WriteString(template[last:])
+	return sb.String(), conditions, nil
+}
+
+// checkUnhandled 模板中没有被识别为FROM/JOIN的表的标识符, 如果是需要加租户条件的表则返回错误
+// 前后是.的标识符为限定的列名或者表名, e,g: user.id, 不检查
+func checkUnhandled(ctx context.Context, tokens []*token, handled map[int]struct{}) error {
+	for i, t := range tokens {
+		if _, ok := handled[i]; ok || !t.isIdentifier() {
+			continue
+		}
+
+		if (i > 0 && tokens[i-1].is(".")) || (i+1 < len(tokens) && tokens[i+1].is(".")) {
+			continue
+		}
+
+		table := t.name()
+		if _, registered := tenant.GetColumn(table); !registered {
+			continue
+		}
+
+		condition, err := tenant.Condition(ctx, table, "")
+		if err != nil {
+			return err
+		}
+
+		if condition != "" {
+			return errors.Errorf("unsupported table reference for tenant isolation, table: %s, position: %d", table, t.start)
+		}
+	}
+	return nil
+}
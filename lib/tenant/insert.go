@@ -0,0 +1,94 @@
+package tenant
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+// FillInsert 插入前设置model的租户字段, 字段已经有值时保持不变, 可以在sqlboiler的BeforeInsertHook中调用
+// 通过boil或db标签匹配租户字段, e,g: TenantID int64 `boil:"tenant_id"`
+func FillInsert(ctx context.Context, table string, model any) error {
+	column, tid, apply, err := Resolve(ctx, table)
+	if err != nil || !apply {
+		return err
+	}
+
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("model must be pointer to struct")
+	}
+	v = v.Elem()
+
+	field, found := findField(v, column)
+	if !found {
+		return errors.Errorf("tenant field not found, table: %s, column: %s", table, column)
+	}
+
+	if !field.IsZero() {
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(tid)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(tid))
+	default:
+		return errors.Errorf("unsupported tenant field type, table: %s, type: %s", table, field.Type())
+	}
+	return nil
+}
+
+func findField(v reflect.Value, column string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		for _, tag := range []string{"boil", "db"} {
+			name, _, _ := strings.Cut(sf.Tag.Get(tag), ",")
+			if name == column {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// Verify 检查查询出来的model是否属于ctx中的租户, 不属于时返回错误, 可以在sqlboiler的AfterSelectHook中调用
+func Verify(ctx context.Context, table string, model any) error {
+	column, tid, apply, err := Resolve(ctx, table)
+	if err != nil || !apply {
+		return err
+	}
+
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.New("model must be pointer to struct")
+	}
+	v = v.Elem()
+
+	field, found := findField(v, column)
+	if !found {
+		return errors.Errorf("tenant field not found, table: %s, column: %s", table, column)
+	}
+
+	var actual int64
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = field.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = int64(field.Uint())
+	default:
+		return errors.Errorf("unsupported tenant field type, table: %s, type: %s", table, field.Type())
+	}
+
+	if actual != tid {
+		return errors.Errorf("row belongs to another tenant, table: %s, tenant: %d", table, actual)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"github.com/hdget/hdsdk/v2/lib/dapr"
+	"github.com/pkg/errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Resolver 从ctx中获取租户id, 返回0表示没有租户
+type Resolver func(ctx context.Context) int64
+
+type ctxKeyTenant struct{}
+
+type ctxKeySkip struct{}
+
+const (
+	defaultColumn = "tenant_id"
+)
+
+var (
+	ErrTenantMissing = errors.New("tenant id missing in context")
+
+	mutex         sync.RWMutex
+	table2column  = make(map[string]string) // 租户隔离的表=>租户字段
+	strict        atomic.Bool
+	defaultLookup Resolver = func(ctx context.Context) int64 {
+		return dapr.Meta().GetTenantId(ctx)
+	}
+)
+
+// Register 注册需要租户隔离的表, column为租户字段, 缺省为tenant_id
+func Register(table string, column ...string) {
+	col := defaultColumn
+	if len(column) > 0 && column[0] != "" {
+		col = column[0]
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	table2column[table] = col
+}
+
+// GetColumn 获取表的租户字段, 表没有注册时返回false
+func GetColumn(table string) (string, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	col, ok := table2column[table]
+	return col, ok
+}
+
+// SetStrict 严格模式下, 访问租户隔离的表时ctx中没有租户id会返回ErrTenantMissing, 否则不加租户条件
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+// SetResolver 设置从ctx中获取租户id的函数, 缺省从dapr的metadata中获取
+func SetResolver(resolver Resolver) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultLookup = resolver
+}
+
+// WithTenant 在ctx中指定租户id, 优先级高于Resolver
+func WithTenant(ctx context.Context, tid int64) context.Context {
+	return context.WithValue(ctx, ctxKeyTenant{}, tid)
+}
+
+// WithoutTenant 显式跳过租户隔离, 用于管理后台等需要跨租户访问的场景
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeySkip{}, true)
+}
+
+// IsSkipped 检查ctx是否显式跳过了租户隔离
+func IsSkipped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	skip, _ := ctx.Value(ctxKeySkip{}).(bool)
+	return skip
+}
+
+// FromContext 获取ctx中的租户id, 没有时返回0
+func FromContext(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+
+	if tid, ok := ctx.Value(ctxKeyTenant{}).(int64); ok {
+		return tid
+	}
+
+	mutex.RLock()
+	resolver := defaultLookup
+	mutex.RUnlock()
+	if resolver == nil {
+		return 0
+	}
+	return resolver(ctx)
+}
+
+// Resolve 获取访问table时需要使用的租户字段和租户id, apply为false表示不需要加租户条件
+// ctx为nil时无法判断租户, 不管是否严格模式都返回ErrTenantMissing
+func Resolve(ctx context.Context, table string) (column string, tid int64, apply bool, err error) {
+	column, ok := GetColumn(table)
+	if !ok || IsSkipped(ctx) {
+		return "", 0, false, nil
+	}
+
+	if ctx == nil {
+		return "", 0, false, errors.Wrapf(ErrTenantMissing, "nil context, table: %s", table)
+	}
+
+	tid = FromContext(ctx)
+	if tid == 0 {
+		if strict.Load() {
+			return "", 0, false, errors.Wrapf(ErrTenantMissing, "table: %s", table)
+		}
+		return "", 0, false, nil
+	}
+	return column, tid, true, nil
+}
+
+// Condition 生成访问table的租户条件, alias为空时使用表名, 不需要租户条件时返回空字符串
+// 租户id为整数, 直接写入SQL, 不会影响其他参数的占位符顺序
+func Condition(ctx context.Context, table, alias string) (string, error) {
+	column, tid, apply, err := Resolve(ctx, table)
+	if err != nil || !apply {
+		return "", err
+	}
+
+	if alias == "" {
+		alias = table
+	}
+	return fmt.Sprintf("%s.%s = %d", alias, column, tid), nil
+}
@@ -48,6 +48,88 @@ func (SortDirection) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_70decb0fb6f436df, []int{0}
 }
 
+// 过滤操作符
+type FilterOperator int32
+
+const (
+	FilterOperator_Eq        FilterOperator = 0
+	FilterOperator_Ne        FilterOperator = 1
+	FilterOperator_Gt        FilterOperator = 2
+	FilterOperator_Gte       FilterOperator = 3
+	FilterOperator_Lt        FilterOperator = 4
+	FilterOperator_Lte       FilterOperator = 5
+	FilterOperator_In        FilterOperator = 6
+	FilterOperator_NotIn     FilterOperator = 7
+	FilterOperator_Like      FilterOperator = 8
+	FilterOperator_IsNull    FilterOperator = 9
+	FilterOperator_IsNotNull FilterOperator = 10
+	FilterOperator_Between   FilterOperator = 11
+)
+
+var FilterOperator_name = map[int32]string{
+	0:  "Eq",
+	1:  "Ne",
+	2:  "Gt",
+	3:  "Gte",
+	4:  "Lt",
+	5:  "Lte",
+	6:  "In",
+	7:  "NotIn",
+	8:  "Like",
+	9:  "IsNull",
+	10: "IsNotNull",
+	11: "Between",
+}
+
+var FilterOperator_value = map[string]int32{
+	"Eq":        0,
+	"Ne":        1,
+	"Gt":        2,
+	"Gte":       3,
+	"Lt":        4,
+	"Lte":       5,
+	"In":        6,
+	"NotIn":     7,
+	"Like":      8,
+	"IsNull":    9,
+	"IsNotNull": 10,
+	"Between":   11,
+}
+
+func (x FilterOperator) String() string {
+	return proto.EnumName(FilterOperator_name, int32(x))
+}
+
+func (FilterOperator) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_70decb0fb6f436df, []int{1}
+}
+
+// 过滤条件的组合方式
+type FilterLogic int32
+
+const (
+	FilterLogic_And FilterLogic = 0
+	FilterLogic_Or  FilterLogic = 1
+)
+
+var FilterLogic_name = map[int32]string{
+	0: "And",
+	1: "Or",
+}
+
+var FilterLogic_value = map[string]int32{
+	"And": 0,
+	"Or":  1,
+}
+
+func (x FilterLogic) String() string {
+	return proto.EnumName(FilterLogic_name, int32(x))
+}
+
+func (FilterLogic) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_70decb0fb6f436df, []int{2}
+}
+
 // 按limit分页
 type ListParam struct {
 	Page     int64 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
@@ -303,45 +385,239 @@ func (m *RouteItem) GetComment() string {
 	return ""
 }
 
+// 过滤条件
+type Filter struct {
+	Field    string         `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Operator FilterOperator `protobuf:"varint,2,opt,name=operator,proto3,enum=hdsdk.protobuf.FilterOperator" json:"operator,omitempty"`
+	Values   []string       `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *Filter) Reset()         { *m = Filter{} }
+func (m *Filter) String() string { return proto.CompactTextString(m) }
+func (*Filter) ProtoMessage()    {}
+func (*Filter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_70decb0fb6f436df, []int{3}
+}
+func (m *Filter) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Filter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Filter.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Filter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Filter.Merge(m, src)
+}
+func (m *Filter) XXX_Size() int {
+	return m.Size()
+}
+func (m *Filter) XXX_DiscardUnknown() {
+	xxx_messageInfo_Filter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Filter proto.InternalMessageInfo
+
+func (m *Filter) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *Filter) GetOperator() FilterOperator {
+	if m != nil {
+		return m.Operator
+	}
+	return FilterOperator_Eq
+}
+
+func (m *Filter) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// 过滤条件组, filters和groups按logic组合
+type FilterGroup struct {
+	Logic   FilterLogic    `protobuf:"varint,1,opt,name=logic,proto3,enum=hdsdk.protobuf.FilterLogic" json:"logic,omitempty"`
+	Filters []*Filter      `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty"`
+	Groups  []*FilterGroup `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+}
+
+func (m *FilterGroup) Reset()         { *m = FilterGroup{} }
+func (m *FilterGroup) String() string { return proto.CompactTextString(m) }
+func (*FilterGroup) ProtoMessage()    {}
+func (*FilterGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_70decb0fb6f436df, []int{4}
+}
+func (m *FilterGroup) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FilterGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FilterGroup.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FilterGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterGroup.Merge(m, src)
+}
+func (m *FilterGroup) XXX_Size() int {
+	return m.Size()
+}
+func (m *FilterGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterGroup proto.InternalMessageInfo
+
+func (m *FilterGroup) GetLogic() FilterLogic {
+	if m != nil {
+		return m.Logic
+	}
+	return FilterLogic_And
+}
+
+func (m *FilterGroup) GetFilters() []*Filter {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+func (m *FilterGroup) GetGroups() []*FilterGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// 排序字段
+type SortParam struct {
+	Field     string        `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Direction SortDirection `protobuf:"varint,2,opt,name=direction,proto3,enum=hdsdk.protobuf.SortDirection" json:"direction,omitempty"`
+}
+
+func (m *SortParam) Reset()         { *m = SortParam{} }
+func (m *SortParam) String() string { return proto.CompactTextString(m) }
+func (*SortParam) ProtoMessage()    {}
+func (*SortParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_70decb0fb6f436df, []int{5}
+}
+func (m *SortParam) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SortParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SortParam.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SortParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SortParam.Merge(m, src)
+}
+func (m *SortParam) XXX_Size() int {
+	return m.Size()
+}
+func (m *SortParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_SortParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SortParam proto.InternalMessageInfo
+
+func (m *SortParam) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *SortParam) GetDirection() SortDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return SortDirection_Asc
+}
+
 func init() {
 	proto.RegisterEnum("hdsdk.protobuf.SortDirection", SortDirection_name, SortDirection_value)
+	proto.RegisterEnum("hdsdk.protobuf.FilterOperator", FilterOperator_name, FilterOperator_value)
+	proto.RegisterEnum("hdsdk.protobuf.FilterLogic", FilterLogic_name, FilterLogic_value)
 	proto.RegisterType((*ListParam)(nil), "hdsdk.protobuf.ListParam")
 	proto.RegisterType((*NextParam)(nil), "hdsdk.protobuf.NextParam")
 	proto.RegisterType((*RouteItem)(nil), "hdsdk.protobuf.RouteItem")
+	proto.RegisterType((*Filter)(nil), "hdsdk.protobuf.Filter")
+	proto.RegisterType((*FilterGroup)(nil), "hdsdk.protobuf.FilterGroup")
+	proto.RegisterType((*SortParam)(nil), "hdsdk.protobuf.SortParam")
 }
 
 func init() { proto.RegisterFile("sdk.proto", fileDescriptor_70decb0fb6f436df) }
 
 var fileDescriptor_70decb0fb6f436df = []byte{
-	// 433 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xc1, 0x8e, 0xd3, 0x30,
-	0x10, 0x86, 0x9b, 0xa6, 0xdb, 0xd6, 0xb3, 0xb4, 0xaa, 0x7c, 0x40, 0x16, 0x12, 0x51, 0x14, 0x71,
-	0xa8, 0x38, 0xb4, 0xd2, 0x72, 0x5c, 0x2e, 0xa0, 0xbd, 0x20, 0xc1, 0xaa, 0xf2, 0x4a, 0x1c, 0xb8,
-	0xa5, 0xf1, 0xd0, 0x58, 0x9b, 0xc4, 0x91, 0xed, 0x00, 0x42, 0x3c, 0x04, 0x8f, 0xc5, 0x71, 0x8f,
-	0x1c, 0x51, 0xfb, 0x04, 0xbc, 0x01, 0xb2, 0x93, 0x76, 0x9b, 0x0b, 0x27, 0xcf, 0xf7, 0xdb, 0x9e,
-	0xf9, 0x67, 0x06, 0x88, 0x11, 0xf7, 0xab, 0x5a, 0x2b, 0xab, 0xe8, 0x3c, 0x17, 0x27, 0xd8, 0x36,
-	0x9f, 0x93, 0x6b, 0x20, 0xef, 0xa5, 0xb1, 0x9b, 0x54, 0xa7, 0x25, 0xa5, 0x30, 0xaa, 0xd3, 0x1d,
-	0xb2, 0x20, 0x0e, 0x96, 0x21, 0xf7, 0x31, 0x7d, 0x06, 0x53, 0x77, 0xde, 0xc9, 0xef, 0xc8, 0x86,
-	0x5e, 0x3f, 0x71, 0xf2, 0x03, 0xc8, 0x2d, 0x7e, 0xeb, 0x3e, 0x3f, 0x85, 0x71, 0x91, 0x1a, 0xbb,
-	0xb9, 0xef, 0xbe, 0x77, 0xf4, 0xbf, 0x04, 0xf4, 0x1a, 0x88, 0x90, 0x1a, 0x33, 0x2b, 0x55, 0xc5,
-	0xc2, 0x38, 0x58, 0xce, 0xaf, 0x9e, 0xaf, 0xfa, 0x0e, 0x57, 0x77, 0x4a, 0xdb, 0x9b, 0xe3, 0x23,
-	0xfe, 0xf8, 0x3e, 0xf9, 0x3b, 0x04, 0xc2, 0x55, 0x63, 0xf1, 0x9d, 0xc5, 0x92, 0xce, 0x61, 0x28,
-	0x45, 0x57, 0x7a, 0x28, 0x05, 0x5d, 0x40, 0x98, 0xd6, 0xb5, 0xaf, 0x48, 0xb8, 0x0b, 0x69, 0x04,
-	0x50, 0x2a, 0xd1, 0x14, 0x78, 0x9b, 0x96, 0xe8, 0xab, 0x11, 0x7e, 0xa6, 0xd0, 0x17, 0x30, 0x6b,
-	0xe9, 0x23, 0x6a, 0xe3, 0x0c, 0x8d, 0xe2, 0x60, 0x79, 0xc1, 0xfb, 0x22, 0x65, 0x30, 0xc9, 0xd3,
-	0x4a, 0x14, 0xa8, 0xd9, 0x85, 0x4f, 0x71, 0x44, 0xd7, 0x28, 0x56, 0xa2, 0x56, 0xb2, 0xb2, 0x6c,
-	0xec, 0xaf, 0x4e, 0xec, 0x6a, 0xe7, 0xd6, 0xd6, 0x1f, 0xd0, 0xe6, 0x4a, 0xb0, 0x49, 0x5b, 0xfb,
-	0x51, 0x71, 0xc3, 0x53, 0x5a, 0xee, 0x64, 0xc5, 0xa6, 0xfe, 0xae, 0x23, 0x97, 0x53, 0x9a, 0x4d,
-	0xb3, 0x2d, 0x64, 0xc6, 0x88, 0xb7, 0x73, 0x62, 0xe7, 0x57, 0x1a, 0x9e, 0x7e, 0xe5, 0x68, 0x6a,
-	0x55, 0x19, 0x64, 0xd0, 0xfa, 0xed, 0x89, 0x34, 0x86, 0xcb, 0x1a, 0x75, 0x29, 0x8d, 0x73, 0x6f,
-	0xd8, 0x65, 0x1c, 0x2e, 0x09, 0x3f, 0x97, 0xdc, 0xa4, 0x1a, 0x5d, 0xb0, 0x27, 0xed, 0xa4, 0x1a,
-	0x5d, 0xb8, 0x1e, 0x33, 0x55, 0x96, 0x58, 0x59, 0x36, 0x6b, 0x7b, 0xec, 0xf0, 0x65, 0x02, 0xb3,
-	0xde, 0x3e, 0xe8, 0x04, 0xc2, 0x37, 0x26, 0x5b, 0x0c, 0xe8, 0x14, 0x46, 0x37, 0x68, 0xb2, 0x45,
-	0xf0, 0xf6, 0xf5, 0xaf, 0x7d, 0x14, 0x3c, 0xec, 0xa3, 0xe0, 0xcf, 0x3e, 0x0a, 0x7e, 0x1e, 0xa2,
-	0xc1, 0xc3, 0x21, 0x1a, 0xfc, 0x3e, 0x44, 0x83, 0x4f, 0xc9, 0x4e, 0xda, 0xbc, 0xd9, 0xae, 0x32,
-	0x55, 0xae, 0x73, 0xb1, 0x43, 0xbb, 0xf6, 0xbb, 0x5e, 0x7f, 0xb9, 0x5a, 0x1f, 0xd7, 0xbd, 0x1d,
-	0xfb, 0xe8, 0xd5, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x81, 0x64, 0xa2, 0xd3, 0xb4, 0x02, 0x00,
-	0x00,
+	// 666 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x41, 0x6f, 0xd3, 0x3c,
+	0x18, 0x6e, 0x92, 0x36, 0x6d, 0xde, 0x7c, 0xad, 0x2c, 0xeb, 0xd3, 0x64, 0x81, 0x88, 0xaa, 0x88,
+	0x43, 0xb5, 0x43, 0x0b, 0xdd, 0x8d, 0x71, 0x61, 0x1a, 0x4c, 0x95, 0x4a, 0x37, 0x65, 0x12, 0x07,
+	0x0e, 0x48, 0x69, 0xe3, 0xb5, 0xd6, 0x92, 0x38, 0xd8, 0xce, 0x86, 0x10, 0x77, 0xae, 0xfc, 0x0b,
+	0xfe, 0x0a, 0xc7, 0x1d, 0x39, 0xa2, 0xed, 0x17, 0xf0, 0x0f, 0x90, 0x9d, 0xb4, 0x5b, 0xd1, 0x40,
+	0xe2, 0x64, 0x3f, 0x8f, 0xdf, 0xd7, 0xcf, 0xe3, 0x27, 0x6f, 0xc0, 0x93, 0xc9, 0xf9, 0xb0, 0x10,
+	0x5c, 0x71, 0xdc, 0x5b, 0x25, 0x1b, 0x30, 0x2f, 0xcf, 0xc2, 0x7d, 0xf0, 0xa6, 0x4c, 0xaa, 0x93,
+	0x58, 0xc4, 0x19, 0xc6, 0xd0, 0x2c, 0xe2, 0x25, 0x25, 0x56, 0xdf, 0x1a, 0x38, 0x91, 0xd9, 0xe3,
+	0x07, 0xd0, 0xd1, 0xeb, 0x29, 0xfb, 0x48, 0x89, 0x6d, 0xf8, 0x0d, 0x0e, 0x3f, 0x81, 0x37, 0xa3,
+	0x1f, 0xea, 0xe6, 0x1d, 0x70, 0xd3, 0x58, 0xaa, 0x93, 0xf3, 0xba, 0xbd, 0x46, 0x7f, 0xbb, 0x00,
+	0xef, 0x83, 0x97, 0x30, 0x41, 0x17, 0x8a, 0xf1, 0x9c, 0x38, 0x7d, 0x6b, 0xd0, 0x1b, 0x3f, 0x1a,
+	0x6e, 0x3b, 0x1c, 0x9e, 0x72, 0xa1, 0x0e, 0xd7, 0x45, 0xd1, 0x6d, 0x7d, 0xf8, 0xd3, 0x06, 0x2f,
+	0xe2, 0xa5, 0xa2, 0x13, 0x45, 0x33, 0xdc, 0x03, 0x9b, 0x25, 0xb5, 0xb4, 0xcd, 0x12, 0x8c, 0xc0,
+	0x89, 0x8b, 0xc2, 0x28, 0x7a, 0x91, 0xde, 0xe2, 0x00, 0x20, 0xe3, 0x49, 0x99, 0xd2, 0x59, 0x9c,
+	0x51, 0xa3, 0xe6, 0x45, 0x77, 0x18, 0xfc, 0x18, 0xba, 0x15, 0x7a, 0x43, 0x85, 0xd4, 0x86, 0x9a,
+	0x7d, 0x6b, 0xd0, 0x8a, 0xb6, 0x49, 0x4c, 0xa0, 0xbd, 0x8a, 0xf3, 0x24, 0xa5, 0x82, 0xb4, 0xcc,
+	0x15, 0x6b, 0xa8, 0x1f, 0x4a, 0xf3, 0xa4, 0xe0, 0x2c, 0x57, 0xc4, 0x35, 0x47, 0x1b, 0xac, 0xb5,
+	0x57, 0x4a, 0x15, 0xaf, 0xa9, 0x5a, 0xf1, 0x84, 0xb4, 0x2b, 0xed, 0x5b, 0x46, 0x87, 0xc7, 0x05,
+	0x5b, 0xb2, 0x9c, 0x74, 0xcc, 0x59, 0x8d, 0xf4, 0x9d, 0x4c, 0x9e, 0x94, 0xf3, 0x94, 0x2d, 0x88,
+	0x67, 0xec, 0x6c, 0xb0, 0xf6, 0xcb, 0x64, 0x14, 0x5f, 0x46, 0x54, 0x16, 0x3c, 0x97, 0x94, 0x40,
+	0xe5, 0x77, 0x8b, 0xc4, 0x7d, 0xf0, 0x0b, 0x2a, 0x32, 0x26, 0xb5, 0x7b, 0x49, 0xfc, 0xbe, 0x33,
+	0xf0, 0xa2, 0xbb, 0x94, 0x4e, 0xaa, 0x14, 0x29, 0xf9, 0xaf, 0x4a, 0xaa, 0x14, 0xa9, 0x7e, 0xe3,
+	0x82, 0x67, 0x19, 0xcd, 0x15, 0xe9, 0x56, 0x6f, 0xac, 0x61, 0x28, 0xc0, 0x7d, 0xc5, 0x52, 0x45,
+	0x05, 0xfe, 0x1f, 0x5a, 0x67, 0x8c, 0xa6, 0x55, 0xe4, 0x5e, 0x54, 0x01, 0xfc, 0x0c, 0x3a, 0xbc,
+	0xa0, 0x22, 0x56, 0x5c, 0x98, 0xe8, 0x7b, 0xe3, 0xe0, 0xf7, 0xef, 0x59, 0xf5, 0x1f, 0xd7, 0x55,
+	0xd1, 0xa6, 0x5e, 0x67, 0x70, 0x11, 0xa7, 0x25, 0x95, 0xc4, 0x31, 0x26, 0x6b, 0x14, 0x7e, 0xb5,
+	0xc0, 0xaf, 0x9a, 0x8e, 0x04, 0x2f, 0x0b, 0xfc, 0x14, 0x5a, 0x29, 0x5f, 0xb2, 0x85, 0x51, 0xee,
+	0x8d, 0x1f, 0xde, 0x2f, 0x30, 0xd5, 0x25, 0x51, 0x55, 0x89, 0x9f, 0x40, 0xfb, 0xcc, 0xb0, 0x92,
+	0xd8, 0x7d, 0x67, 0xe0, 0x8f, 0x77, 0xee, 0x6f, 0x8a, 0xd6, 0x65, 0x78, 0x0f, 0xdc, 0xa5, 0x56,
+	0xab, 0xcc, 0xf8, 0x7f, 0x52, 0x31, 0x8e, 0xa2, 0xba, 0x34, 0x7c, 0x07, 0x9e, 0x9e, 0xd6, 0xea,
+	0x7f, 0xb8, 0x3f, 0xa0, 0xad, 0x89, 0xb7, 0xff, 0x6d, 0xe2, 0x77, 0x43, 0xe8, 0x6e, 0x9d, 0xe1,
+	0x36, 0x38, 0x2f, 0xe4, 0x02, 0x35, 0x70, 0x07, 0x9a, 0x87, 0x54, 0x2e, 0x90, 0xb5, 0xfb, 0xd9,
+	0x82, 0xde, 0x76, 0xc4, 0xd8, 0x05, 0xfb, 0xe5, 0x7b, 0xd4, 0xd0, 0xeb, 0x8c, 0x22, 0x4b, 0xaf,
+	0x47, 0x0a, 0xd9, 0xba, 0xfb, 0x48, 0x51, 0xe4, 0x68, 0x62, 0xaa, 0x50, 0x53, 0x13, 0x53, 0x45,
+	0x51, 0x4b, 0x13, 0x93, 0x1c, 0xb9, 0xd8, 0x83, 0xd6, 0x8c, 0xab, 0x49, 0x8e, 0xda, 0x5a, 0x61,
+	0xca, 0xce, 0x29, 0xea, 0x60, 0x00, 0x77, 0x22, 0x67, 0x65, 0x9a, 0x22, 0x0f, 0x77, 0xc1, 0x9b,
+	0xc8, 0x19, 0x57, 0x06, 0x02, 0xf6, 0xa1, 0x7d, 0x40, 0xd5, 0x25, 0xa5, 0x39, 0xf2, 0x77, 0x03,
+	0xf0, 0xef, 0x7c, 0x0a, 0xe3, 0x35, 0x4f, 0x2a, 0x1b, 0xc7, 0x02, 0x59, 0x07, 0xcf, 0xbf, 0x5d,
+	0x07, 0xd6, 0xd5, 0x75, 0x60, 0xfd, 0xb8, 0x0e, 0xac, 0x2f, 0x37, 0x41, 0xe3, 0xea, 0x26, 0x68,
+	0x7c, 0xbf, 0x09, 0x1a, 0x6f, 0xc3, 0x25, 0x53, 0xab, 0x72, 0x3e, 0x5c, 0xf0, 0x6c, 0xb4, 0x4a,
+	0x96, 0x54, 0x8d, 0x4c, 0x42, 0xa3, 0x8b, 0xf1, 0x68, 0x1d, 0xd2, 0xdc, 0x35, 0xbb, 0xbd, 0x5f,
+	0x03, 0x00, 0x00, 0x22, 0xad, 0xfd, 0xdc, 0x04, 0x00, 0x00,
 }
 
 func (m *ListParam) Marshal() (dAtA []byte, err error) {
@@ -523,6 +799,141 @@ func (m *RouteItem) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *Filter) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Filter) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Filter) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintSdk(dAtA, i, uint64(len(m.Values[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Operator != 0 {
+		i = encodeVarintSdk(dAtA, i, uint64(m.Operator))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintSdk(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FilterGroup) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FilterGroup) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FilterGroup) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Groups) > 0 {
+		for iNdEx := len(m.Groups) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Groups[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSdk(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Filters) > 0 {
+		for iNdEx := len(m.Filters) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Filters[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSdk(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Logic != 0 {
+		i = encodeVarintSdk(dAtA, i, uint64(m.Logic))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SortParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SortParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SortParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Direction != 0 {
+		i = encodeVarintSdk(dAtA, i, uint64(m.Direction))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintSdk(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintSdk(dAtA []byte, offset int, v uint64) int {
 	offset -= sovSdk(v)
 	base := offset
@@ -626,20 +1037,82 @@ func (m *RouteItem) Size() (n int) {
 	return n
 }
 
-func sovSdk(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
+func (m *Filter) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovSdk(uint64(l))
+	}
+	if m.Operator != 0 {
+		n += 1 + sovSdk(uint64(m.Operator))
+	}
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovSdk(uint64(l))
+		}
+	}
+	return n
 }
-func sozSdk(x uint64) (n int) {
-	return sovSdk(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+func (m *FilterGroup) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Logic != 0 {
+		n += 1 + sovSdk(uint64(m.Logic))
+	}
+	if len(m.Filters) > 0 {
+		for _, e := range m.Filters {
+			l = e.Size()
+			n += 1 + l + sovSdk(uint64(l))
+		}
+	}
+	if len(m.Groups) > 0 {
+		for _, e := range m.Groups {
+			l = e.Size()
+			n += 1 + l + sovSdk(uint64(l))
+		}
+	}
+	return n
 }
-func (m *ListParam) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
+
+func (m *SortParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovSdk(uint64(l))
+	}
+	if m.Direction != 0 {
+		n += 1 + sovSdk(uint64(m.Direction))
+	}
+	return n
+}
+
+func sovSdk(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozSdk(x uint64) (n int) {
+	return sovSdk(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ListParam) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
 				return ErrIntOverflowSdk
 			}
 			if iNdEx >= l {
@@ -1241,6 +1714,377 @@ func (m *RouteItem) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Filter) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSdk
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Filter: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Filter: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSdk
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operator", wireType)
+			}
+			m.Operator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Operator |= FilterOperator(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSdk
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSdk(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FilterGroup) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSdk
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FilterGroup: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FilterGroup: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Logic", wireType)
+			}
+			m.Logic = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Logic |= FilterLogic(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filters", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSdk
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filters = append(m.Filters, &Filter{})
+			if err := m.Filters[len(m.Filters)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Groups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSdk
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Groups = append(m.Groups, &FilterGroup{})
+			if err := m.Groups[len(m.Groups)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSdk(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SortParam) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSdk
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SortParam: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SortParam: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSdk
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Direction", wireType)
+			}
+			m.Direction = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSdk
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Direction |= SortDirection(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSdk(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSdk
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipSdk(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Balancer 读写分离, 读请求在健康的从库间负载均衡, 没有健康的从库时回退到主库
+type Balancer[T comparable] struct {
+	master    T
+	nodes     []*node[T]
+	strategy  string
+	counter   atomic.Uint64
+	mutex     sync.Mutex // 保护加权轮询的currentWeight
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+type node[T comparable] struct {
+	client        T
+	weight        int
+	currentWeight int
+	healthy       atomic.Bool
+}
+
+// pinger *sql.DB和*sqlx.DB都实现了该接口
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+const (
+	ReadStrategyRoundRobin = "round_robin" // 轮询, 缺省策略
+	ReadStrategyWeighted   = "weighted"    // 按从库的weight加权轮询
+
+	healthCheckInterval = 10 * time.Second
+	healthCheckTimeout  = 3 * time.Second
+)
+
+// WeightConfig 从库权重, 配置在实例的同一级
+type WeightConfig struct {
+	Weight int `mapstructure:"weight"` // 从库权重, 只在read_strategy为weighted时有效
+}
+
+// weighter 嵌入了WeightConfig的配置实现了该接口
+type weighter interface {
+	GetWeight() int
+}
+
+func (c WeightConfig) GetWeight() int {
+	return c.Weight
+}
+
+// GetSlaveWeights 获取与slaves一一对应的权重
+func GetSlaveWeights[T weighter](slaves []T) []int {
+	weights := make([]int, len(slaves))
+	for i, slave := range slaves {
+		weights[i] = slave.GetWeight()
+	}
+	return weights
+}
+
+// NewBalancer 创建读写分离的负载均衡器, weights与slaves一一对应, weight<=0时按1处理
+func NewBalancer[T comparable](master T, slaves []T, weights []int, strategy string) (*Balancer[T], error) {
+	if strategy == "" {
+		strategy = ReadStrategyRoundRobin
+	}
+
+	if strategy != ReadStrategyRoundRobin && strategy != ReadStrategyWeighted {
+		return nil, errors.Errorf("invalid read strategy: %s", strategy)
+	}
+
+	b := &Balancer[T]{
+		master:    master,
+		nodes:     make([]*node[T], 0, len(slaves)),
+		strategy:  strategy,
+		closeChan: make(chan struct{}),
+	}
+
+	var zero T
+	for i, slave := range slaves {
+		if slave == zero {
+			continue
+		}
+
+		weight := 1
+		if i < len(weights) && weights[i] > 0 {
+			weight = weights[i]
+		}
+
+		n := &node[T]{client: slave, weight: weight}
+		n.healthy.Store(true)
+		b.nodes = append(b.nodes, n)
+	}
+
+	if len(b.nodes) > 0 {
+		go b.run()
+	}
+
+	return b, nil
+}
+
+// Writer 获取主库
+func (b *Balancer[T]) Writer() T {
+	return b.master
+}
+
+// Reader 获取一个健康的从库, ctx中要求读主库或者没有健康的从库时返回主库
+func (b *Balancer[T]) Reader(ctx context.Context) T {
+	if len(b.nodes) == 0 || IsMasterForced(ctx) {
+		return b.master
+	}
+
+	var n *node[T]
+	switch b.strategy {
+	case ReadStrategyWeighted:
+		n = b.pickWeighted()
+	default:
+		n = b.pickRoundRobin()
+	}
+
+	if n == nil {
+		return b.master
+	}
+	return n.client
+}
+
+func (b *Balancer[T]) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closeChan)
+	})
+}
+
+func (b *Balancer[T]) pickRoundRobin() *node[T] {
+	total := uint64(len(b.nodes))
+	start := b.counter.Add(1)
+	for i := uint64(0); i < total; i++ {
+		n := b.nodes[(start+i)%total]
+		if n.healthy.Load() {
+			return n
+		}
+	}
+	return nil
+}
+
+// pickWeighted 平滑加权轮询, 参考nginx的实现
+func (b *Balancer[T]) pickWeighted() *node[T] {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var (
+		picked      *node[T]
+		totalWeight int
+	)
+	for _, n := range b.nodes {
+		if !n.healthy.Load() {
+			continue
+		}
+
+		n.currentWeight += n.weight
+		totalWeight += n.weight
+		if picked == nil || n.currentWeight > picked.currentWeight {
+			picked = n
+		}
+	}
+
+	if picked != nil {
+		picked.currentWeight -= totalWeight
+	}
+	return picked
+}
+
+// run 定期检查从库的健康状态, 失败的从库暂时被排除, 检查恢复后重新加入
+func (b *Balancer[T]) run() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.closeChan:
+			return
+		case <-ticker.C:
+			for _, n := range b.nodes {
+				n.healthy.Store(n.check())
+			}
+		}
+	}
+}
+
+func (n *node[T]) check() bool {
+	p, ok := any(n.client).(pinger)
+	if !ok {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	return p.PingContext(ctx) == nil
+}
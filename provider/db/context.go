@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type ctxKeyForceMaster struct{}
+
+type ctxKeySession struct{}
+
+// session 请求级别的读写状态, 写入后同一个请求中的后续读请求都走主库
+type session struct {
+	written atomic.Bool
+}
+
+// WithMaster 返回的ctx中所有的读请求都走主库
+func WithMaster(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyForceMaster{}, true)
+}
+
+// WithSession 在请求开始时调用, 之后可以通过MarkWritten实现读己之写(read-your-writes)
+func WithSession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(ctxKeySession{}).(*session); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKeySession{}, &session{})
+}
+
+// MarkWritten 标记请求中已经发生了写操作, 同一个请求中之后的读请求都走主库
+// ctx必须是WithSession返回的ctx或者其派生的ctx, 否则不生效
+// 通过DbClient的ExecContext以及QueryContext/GetContext等执行INSERT/UPDATE/DELETE时会自动调用,
+// 不带ctx的Exec等方法和不经过DbClient的写操作需要手动调用
+func MarkWritten(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	if s, ok := ctx.Value(ctxKeySession{}).(*session); ok {
+		s.written.Store(true)
+	}
+}
+
+// IsMasterForced 检查ctx是否要求读请求走主库
+func IsMasterForced(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	if force, _ := ctx.Value(ctxKeyForceMaster{}).(bool); force {
+		return true
+	}
+
+	if s, ok := ctx.Value(ctxKeySession{}).(*session); ok {
+		return s.written.Load()
+	}
+	return false
+}
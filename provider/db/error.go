@@ -0,0 +1,30 @@
+package db
+
+import (
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const (
+	mysqlErrDuplicateEntry     = 1062
+	postgresErrUniqueViolation = "23505"
+	// sqliteErrUniqueConstraint modernc.org/sqlite的错误信息, 避免依赖sqlite驱动
+	sqliteErrUniqueConstraint = "UNIQUE constraint failed"
+)
+
+// IsDuplicateKeyError 判断是否为主键或唯一键冲突
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDuplicateEntry
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresErrUniqueViolation
+	}
+
+	return err != nil && strings.Contains(err.Error(), sqliteErrUniqueConstraint)
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QueryEvent 一次数据库调用的信息, Before时只有Method, Query, Args和Start有值
+type QueryEvent struct {
+	Method   string // Exec, Query, QueryRow, Get, Select
+	Query    string
+	Args     []any
+	LogArgs  []any // 用于打印日志的参数, 缺省和Args相同, 可以被脱敏hook修改
+	Start    time.Time
+	Duration time.Duration
+	Rows     int64 // Exec为影响的行数, Get/Select为返回的行数, Query/QueryRow无法获取时为-1
+	Err      error
+}
+
+// QueryHook 每次数据库调用前后执行, Before按注册顺序执行, After按注册的逆序执行
+// Before返回的ctx会传给后续的hook和数据库调用, 可以用于传递trace等信息
+type QueryHook interface {
+	Before(ctx context.Context, event *QueryEvent) context.Context
+	After(ctx context.Context, event *QueryEvent)
+}
+
+var (
+	globalHooksMutex sync.RWMutex
+	globalHooks      []QueryHook
+)
+
+// RegisterQueryHook 注册作用于所有数据库连接的hook, 在配置的内置hook之后执行
+func RegisterQueryHook(hook QueryHook) {
+	globalHooksMutex.Lock()
+	defer globalHooksMutex.Unlock()
+	globalHooks = append(globalHooks, hook)
+}
+
+func getQueryHooks(hooks []QueryHook) []QueryHook {
+	globalHooksMutex.RLock()
+	defer globalHooksMutex.RUnlock()
+	if len(globalHooks) == 0 {
+		return hooks
+	}
+
+	all := make([]QueryHook, 0, len(hooks)+len(globalHooks))
+	all = append(all, hooks...)
+	return append(all, globalHooks...)
+}
+
+// runHooks 依次执行Before, 执行fn后逆序执行After
+func runHooks(ctx context.Context, hooks []QueryHook, method, query string, args []any, fn func(ctx context.Context, event *QueryEvent) error) error {
+	hooks = getQueryHooks(hooks)
+	if len(hooks) == 0 {
+		return fn(ctx, &QueryEvent{Method: method, Query: query, Args: args, Rows: -1})
+	}
+
+	event := &QueryEvent{
+		Method:  method,
+		Query:   query,
+		Args:    args,
+		LogArgs: args,
+		Start:   time.Now(),
+		Rows:    -1,
+	}
+
+	for _, hook := range hooks {
+		ctx = hook.Before(ctx, event)
+	}
+
+	event.Err = fn(ctx, event)
+	event.Duration = time.Since(event.Start)
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].After(ctx, event)
+	}
+	return event.Err
+}
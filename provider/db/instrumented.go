@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// InstrumentedDB 在sqlx.DB的基础上增加缺省的查询超时时间和查询hook
+// Exec/Get/Select以及对应的Context方法, 如果ctx没有设置deadline, 则使用缺省的查询超时时间
+// Query/QueryRow/BeginTx返回的结果在调用结束后仍然需要使用ctx, 所以不设置缺省超时, 由调用方通过ctx控制
+// sqlboiler通过boil.SetDB使用的也是该连接, 所以sqlboiler执行的查询同样会经过hook
+// 通过InTx开启的事务继承该连接的超时时间和hook, 所以事务中的查询同样会经过hook
+type InstrumentedDB struct {
+	*sqlx.DB
+	*instrumentation
+}
+
+// instrumentation 缺省查询超时时间和查询hook, InstrumentedDB和事务中的txClient共用
+type instrumentation struct {
+	queryTimeout time.Duration
+	hooks        []QueryHook
+}
+
+// queryer sqlx.DB和sqlx.Tx都实现了该接口
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// instrumented 嵌入了InstrumentedDB的client实现了该接口, 用于开启事务时继承超时时间和hook
+type instrumented interface {
+	getInstrumentation() *instrumentation
+}
+
+// NewInstrumentedDB queryTimeout单位为秒, <=0表示不设置缺省超时
+func NewInstrumentedDB(db *sqlx.DB, queryTimeout int, hooks ...QueryHook) *InstrumentedDB {
+	return &InstrumentedDB{
+		DB: db,
+		instrumentation: &instrumentation{
+			queryTimeout: time.Duration(queryTimeout) * time.Second,
+			hooks:        hooks,
+		},
+	}
+}
+
+func (t *InstrumentedDB) Exec(query string, args ...any) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t *InstrumentedDB) Query(query string, args ...any) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t *InstrumentedDB) QueryRow(query string, args ...any) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+func (t *InstrumentedDB) Queryx(query string, args ...any) (*sqlx.Rows, error) {
+	return t.QueryxContext(context.Background(), query, args...)
+}
+
+func (t *InstrumentedDB) Get(dest any, query string, args ...any) error {
+	return t.GetContext(context.Background(), dest, query, args...)
+}
+
+func (t *InstrumentedDB) Select(dest any, query string, args ...any) error {
+	return t.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (t *InstrumentedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.exec(ctx, t.DB, query, args...)
+}
+
+func (t *InstrumentedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.query(ctx, t.DB, query, args...)
+}
+
+func (t *InstrumentedDB) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return t.queryx(ctx, t.DB, query, args...)
+}
+
+func (t *InstrumentedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.queryRow(ctx, t.DB, query, args...)
+}
+
+func (t *InstrumentedDB) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return t.get(ctx, t.DB, dest, query, args...)
+}
+
+func (t *InstrumentedDB) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return t.selectRows(ctx, t.DB, dest, query, args...)
+}
+
+func (t *InstrumentedDB) getInstrumentation() *instrumentation {
+	return t.instrumentation
+}
+
+/* instrumentation */
+
+func (in *instrumentation) exec(ctx context.Context, q queryer, query string, args ...any) (sql.Result, error) {
+	ctx, cancel := in.withTimeout(ctx)
+	defer cancel()
+
+	// 同一个请求中之后的读请求走主库
+	MarkWritten(ctx)
+
+	var result sql.Result
+	err := runHooks(ctx, in.hooks, "Exec", query, args, func(ctx context.Context, event *QueryEvent) error {
+		var err error
+		result, err = q.ExecContext(ctx, query, args...)
+		if err == nil {
+			if affected, e := result.RowsAffected(); e == nil {
+				event.Rows = affected
+			}
+		}
+		return err
+	})
+	return result, err
+}
+
+func (in *instrumentation) query(ctx context.Context, q queryer, query string, args ...any) (*sql.Rows, error) {
+	markIfWrite(ctx, query)
+	var rows *sql.Rows
+	err := runHooks(ctx, in.hooks, "Query", query, args, func(ctx context.Context, event *QueryEvent) error {
+		var err error
+		rows, err = q.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (in *instrumentation) queryx(ctx context.Context, q queryer, query string, args ...any) (*sqlx.Rows, error) {
+	markIfWrite(ctx, query)
+	var rows *sqlx.Rows
+	err := runHooks(ctx, in.hooks, "Query", query, args, func(ctx context.Context, event *QueryEvent) error {
+		var err error
+		rows, err = q.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (in *instrumentation) queryRow(ctx context.Context, q queryer, query string, args ...any) *sql.Row {
+	markIfWrite(ctx, query)
+	var row *sql.Row
+	_ = runHooks(ctx, in.hooks, "QueryRow", query, args, func(ctx context.Context, event *QueryEvent) error {
+		row = q.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+func (in *instrumentation) get(ctx context.Context, q queryer, dest any, query string, args ...any) error {
+	ctx, cancel := in.withTimeout(ctx)
+	defer cancel()
+
+	markIfWrite(ctx, query)
+
+	return runHooks(ctx, in.hooks, "Get", query, args, func(ctx context.Context, event *QueryEvent) error {
+		err := q.GetContext(ctx, dest, query, args...)
+		if err == nil {
+			event.Rows = 1
+		}
+		return err
+	})
+}
+
+func (in *instrumentation) selectRows(ctx context.Context, q queryer, dest any, query string, args ...any) error {
+	ctx, cancel := in.withTimeout(ctx)
+	defer cancel()
+
+	markIfWrite(ctx, query)
+
+	return runHooks(ctx, in.hooks, "Select", query, args, func(ctx context.Context, event *QueryEvent) error {
+		err := q.SelectContext(ctx, dest, query, args...)
+		if err == nil {
+			event.Rows = sliceLen(dest)
+		}
+		return err
+	})
+}
+
+// markIfWrite 通过Query/Get执行的写语句, e,g: INSERT ... RETURNING, 同样标记请求中已经发生了写操作
+func markIfWrite(ctx context.Context, query string) {
+	if isWriteQuery(query) {
+		MarkWritten(ctx)
+	}
+}
+
+// isWriteQuery 根据第一个关键字判断是否是写语句
+func isWriteQuery(query string) bool {
+	keyword := strings.TrimLeftFunc(query, unicode.IsSpace)
+	if pos := strings.IndexFunc(keyword, unicode.IsSpace); pos >= 0 {
+		keyword = keyword[:pos]
+	}
+
+	switch strings.ToUpper(keyword) {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE", "MERGE":
+		return true
+	}
+	return false
+}
+
+// withTimeout ctx已经设置了deadline时以ctx为准
+func (in *instrumentation) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if in.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, in.queryTimeout)
+}
+
+// sliceLen dest为指向slice的指针
+func sliceLen(dest any) int64 {
+	v := reflect.ValueOf(dest)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return -1
+	}
+	return int64(v.Len())
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// MysqlDsnConfig MySQL连接参数, 时间单位为秒
+type MysqlDsnConfig struct {
+	ReadTimeout  int               `mapstructure:"read_timeout"`
+	WriteTimeout int               `mapstructure:"write_timeout"`
+	Loc          string            `mapstructure:"loc"`      // 解析时间使用的时区, 缺省为Local, e,g: UTC, Asia/Shanghai
+	Params       map[string]string `mapstructure:"params"`   // 额外的DSN参数, e,g: {collation = "utf8mb4_general_ci"}
+	Tls          string            `mapstructure:"tls"`      // true, false, skip-verify, preferred, 配置了tls_ca时自动启用
+	TlsCa        string            `mapstructure:"tls_ca"`   // CA证书文件
+	TlsCert      string            `mapstructure:"tls_cert"` // 客户端证书文件
+	TlsKey       string            `mapstructure:"tls_key"`  // 客户端私钥文件
+}
+
+// GetMysqlDsn 构造MySQL的DSN, timeout为连接超时时间
+// 缺省参数: charset=utf8mb4&parseTime=true&loc=Local
+func GetMysqlDsn(user, password, host string, port int, database string, timeout int, c MysqlDsnConfig) (string, error) {
+	conf := mysql.NewConfig()
+	conf.User = user
+	conf.Passwd = password
+	conf.Net = "tcp"
+	conf.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	conf.DBName = database
+	conf.ParseTime = true
+	conf.Params = map[string]string{"charset": "utf8mb4"}
+	for k, v := range c.Params {
+		conf.Params[k] = v
+	}
+
+	loc := c.Loc
+	if loc == "" {
+		loc = "Local"
+	}
+
+	var err error
+	conf.Loc, err = time.LoadLocation(loc)
+	if err != nil {
+		return "", errors.Wrapf(err, "load location, loc: %s", loc)
+	}
+
+	if timeout > 0 {
+		conf.Timeout = time.Duration(timeout) * time.Second
+	}
+	if c.ReadTimeout > 0 {
+		conf.ReadTimeout = time.Duration(c.ReadTimeout) * time.Second
+	}
+	if c.WriteTimeout > 0 {
+		conf.WriteTimeout = time.Duration(c.WriteTimeout) * time.Second
+	}
+
+	conf.TLSConfig, err = c.getTlsConfig(host, port)
+	if err != nil {
+		return "", err
+	}
+
+	return conf.FormatDSN(), nil
+}
+
+// getTlsConfig 配置了证书时注册自定义的TLS配置, 返回DSN中tls参数的值
+func (c MysqlDsnConfig) getTlsConfig(host string, port int) (string, error) {
+	if c.TlsCa == "" && c.TlsCert == "" {
+		return c.Tls, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: c.Tls == "skip-verify",
+	}
+
+	if c.TlsCa != "" {
+		pem, err := os.ReadFile(c.TlsCa)
+		if err != nil {
+			return "", errors.Wrap(err, "read tls ca")
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return "", errors.New("append tls ca")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if c.TlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.TlsCert, c.TlsKey)
+		if err != nil {
+			return "", errors.Wrap(err, "load tls cert")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// 每个实例注册一个名字唯一的TLS配置
+	name := fmt.Sprintf("custom-%s-%d", host, port)
+	err := mysql.RegisterTLSConfig(name, tlsConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "register tls config")
+	}
+	return name, nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go.uber.org/fx"
+	"time"
+)
+
+// PoolConfig 连接池配置, 时间单位为秒, 值为0时使用缺省值
+type PoolConfig struct {
+	MaxOpenConns    int `mapstructure:"max_open_conns"`     // 最大连接数, 缺省不限制
+	MaxIdleConns    int `mapstructure:"max_idle_conns"`     // 最大空闲连接数, 缺省为2
+	ConnMaxIdleTime int `mapstructure:"conn_max_idle_time"` // 空闲连接最长保留时间, 缺省不限制
+	ConnMaxLifetime int `mapstructure:"conn_max_lifetime"`  // 连接最长使用时间, 缺省为180秒
+}
+
+const (
+	defaultConnMaxLifetime = 3 * time.Minute
+)
+
+// Apply 设置连接池参数
+func (c PoolConfig) Apply(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+
+	if c.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(time.Duration(c.ConnMaxIdleTime) * time.Second)
+	}
+
+	// https://www.alexedwards.net/blog/configuring-sqldb
+	// https://making.pusher.com/production-ready-connection-pooling-in-go
+	// Avoid issue:
+	// packets.go:123: closing bad idle connection: EOF
+	// connection.go:173: driver: bad connection
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetime) * time.Second)
+	} else {
+		db.SetConnMaxLifetime(defaultConnMaxLifetime)
+	}
+}
+
+// statser *sql.DB和*sqlx.DB都实现了该接口
+type statser interface {
+	Stats() sql.DBStats
+}
+
+// CollectStats 收集所有连接池的统计信息, key为default, master, slave.<index>, item.<name>
+func CollectStats[T comparable](defaultDb, masterDb T, slaveDbs []T, extraDbs map[string]T) map[string]sql.DBStats {
+	stats := make(map[string]sql.DBStats)
+	collect := func(name string, client T) {
+		if s, ok := any(client).(statser); ok {
+			stats[name] = s.Stats()
+		}
+	}
+
+	var zero T
+	if defaultDb != zero {
+		collect("default", defaultDb)
+	}
+
+	if masterDb != zero {
+		collect("master", masterDb)
+	}
+
+	for i, slave := range slaveDbs {
+		if slave != zero {
+			collect(fmt.Sprintf("slave.%d", i), slave)
+		}
+	}
+
+	for name, extra := range extraDbs {
+		if extra != zero {
+			collect("item."+name, extra)
+		}
+	}
+	return stats
+}
+
+// closer *sql.DB和*sqlx.DB都实现了该接口
+type closer interface {
+	Close() error
+}
+
+// CloseAll 关闭所有连接池
+func CloseAll[T comparable](defaultDb, masterDb T, slaveDbs []T, extraDbs map[string]T) error {
+	clients := append([]T{defaultDb, masterDb}, slaveDbs...)
+	for _, extra := range extraDbs {
+		clients = append(clients, extra)
+	}
+
+	var (
+		zero T
+		errs []error
+	)
+	for _, client := range clients {
+		if c, ok := any(client).(closer); ok && client != zero {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseOnStop sdk停止时关闭provider, provider没有实现Close时忽略
+func CloseOnStop(lc fx.Lifecycle, provider any) {
+	if c, ok := provider.(closer); ok {
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				return c.Close()
+			},
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+// QueryLogConfig 慢查询日志配置, 配置在实例的同一级
+type QueryLogConfig struct {
+	SlowQueryThreshold int  `mapstructure:"slow_query_threshold"` // 慢查询阈值, 单位为毫秒, 0表示不记录慢查询
+	LogArgs            bool `mapstructure:"log_args"`             // 日志中打印原始参数, 缺省对字符串参数脱敏
+	Debug              bool `mapstructure:"debug"`                // 调试模式, 慢SELECT额外记录EXPLAIN结果
+}
+
+// redactHook 日志中的字符串和二进制参数脱敏, 数字/布尔/时间等保持原样
+type redactHook struct{}
+
+// slowQueryHook 执行时间超过阈值的查询打印警告日志
+type slowQueryHook struct {
+	logger    intf.LoggerProvider
+	threshold time.Duration
+	explainDb *sqlx.DB // 不为nil时对慢SELECT执行EXPLAIN, 使用原始连接避免再次触发hook
+}
+
+const (
+	redactedValue  = "******"
+	explainTimeout = 3 * time.Second
+)
+
+// NewQueryLogHooks 根据配置生成内置的hook, db为执行EXPLAIN的原始连接
+func NewQueryLogHooks(logger intf.LoggerProvider, db *sqlx.DB, c QueryLogConfig) []QueryHook {
+	var hooks []QueryHook
+	if !c.LogArgs {
+		hooks = append(hooks, &redactHook{})
+	}
+
+	if logger == nil || c.SlowQueryThreshold <= 0 {
+		return hooks
+	}
+
+	h := &slowQueryHook{
+		logger:    logger,
+		threshold: time.Duration(c.SlowQueryThreshold) * time.Millisecond,
+	}
+	if c.Debug {
+		h.explainDb = db
+	}
+	return append(hooks, h)
+}
+
+func (h *redactHook) Before(ctx context.Context, event *QueryEvent) context.Context {
+	event.LogArgs = RedactArgs(event.Args)
+	return ctx
+}
+
+func (h *redactHook) After(ctx context.Context, event *QueryEvent) {}
+
+// RedactArgs 将字符串和二进制参数替换为******
+func RedactArgs(args []any) []any {
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case string, []byte, *string:
+			redacted[i] = redactedValue
+		default:
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
+
+func (h *slowQueryHook) Before(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *slowQueryHook) After(ctx context.Context, event *QueryEvent) {
+	if event.Duration < h.threshold {
+		return
+	}
+
+	kvs := []any{
+		"method", event.Method,
+		"query", event.Query,
+		"args", event.LogArgs,
+		"duration", event.Duration.String(),
+		"rows", event.Rows,
+	}
+	if event.Err != nil {
+		kvs = append(kvs, "err", event.Err)
+	}
+
+	if h.explainDb != nil && isSelect(event.Query) {
+		plan, err := h.explain(event.Query, event.Args)
+		if err != nil {
+			kvs = append(kvs, "explain_err", err)
+		} else {
+			kvs = append(kvs, "explain", plan)
+		}
+	}
+
+	h.logger.Warn("slow query", kvs...)
+}
+
+// explain 原始查询的ctx可能已经超时, 所以使用新的ctx
+func (h *slowQueryHook) explain(query string, args []any) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	rows, err := h.explainDb.QueryxContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plan []map[string]any
+	for rows.Next() {
+		row := make(map[string]any)
+		if err = rows.MapScan(row); err != nil {
+			return nil, err
+		}
+
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+		plan = append(plan, row)
+	}
+	return plan, rows.Err()
+}
+
+func isSelect(query string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT")
+}
@@ -2,6 +2,7 @@ package sqlboiler_mysql
 
 import (
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"go.uber.org/fx"
 )
 
@@ -10,5 +11,8 @@ var Capability = &intf.Capability{
 	Module: fx.Module(
 		string(intf.ProviderNameDbSqlBoilerMysql),
 		fx.Provide(New),
+		fx.Invoke(func(lc fx.Lifecycle, p intf.DbProvider) {
+			db.CloseOnStop(lc, p)
+		}),
 	),
 }
@@ -1,59 +1,36 @@
 package sqlboiler_mysql
 
 import (
-	"database/sql"
-	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hdget/hdsdk/v2/intf"
-	"time"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/jmoiron/sqlx"
 )
 
+// mysqlClient 使用sqlx.DB, 除了sqlboiler需要的Executor接口外, Get/Select/Rebind也可以直接使用
 type mysqlClient struct {
-	*sql.DB
+	*db.InstrumentedDB
 }
 
-const (
-	// 这里设置解析时间类型https://github.com/go-sql-driver/mysql#timetime-support
-	// DSN (Data Type NickName): username:password@protocol(address)/dbname?param=value
-	dsnTemplate = "%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local"
-)
-
-func newClient(c *mysqlConfig) (intf.DbClient, error) {
-	// 构造连接参数
-	dsn := fmt.Sprintf(dsnTemplate, c.User, c.Password, c.Host, c.Port, c.Database)
-	db, err := sql.Open("mysql", dsn)
+func newClient(c *mysqlConfig, logger intf.LoggerProvider) (intf.DbClient, error) {
+	// 构造连接参数, 解析时间类型参考https://github.com/go-sql-driver/mysql#timetime-support
+	dsn, err := db.GetMysqlDsn(c.User, c.Password, c.Host, c.Port, c.Database, c.Timeout, c.MysqlDsnConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.Ping()
+	sqlDb, err := sqlx.Open("mysql", dsn)
 	if err != nil {
-		_ = db.Close()
 		return nil, err
 	}
 
-	// https://www.alexedwards.net/blog/configuring-sqldb
-	// https://making.pusher.com/production-ready-connection-pooling-in-go
-	// Avoid issue:
-	// packets.go:123: closing bad idle connection: EOF
-	// connection.go:173: driver: bad connection
-	db.SetConnMaxLifetime(3 * time.Minute)
-
-	return &mysqlClient{DB: db}, nil
-}
-
-func (m mysqlClient) Close() error {
-	return m.DB.Close()
-}
-
-func (m mysqlClient) Get(dest interface{}, query string, args ...interface{}) error {
-	return nil
-}
+	err = sqlDb.Ping()
+	if err != nil {
+		_ = sqlDb.Close()
+		return nil, err
+	}
 
-func (m mysqlClient) Select(dest interface{}, query string, args ...interface{}) error {
-	return nil
-}
+	c.PoolConfig.Apply(sqlDb.DB)
 
-func (m mysqlClient) Rebind(query string) string {
-	return ""
+	return &mysqlClient{InstrumentedDB: db.NewInstrumentedDB(sqlDb, c.QueryTimeout, db.NewQueryLogHooks(logger, sqlDb, c.QueryLogConfig)...)}, nil
 }
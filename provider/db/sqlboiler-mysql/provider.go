@@ -1,7 +1,12 @@
 package sqlboiler_mysql
 
 import (
+	"context"
+	"database/sql"
+	"github.com/elliotchance/pie/v2"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/pkg/errors"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 )
@@ -13,6 +18,8 @@ type mysqlProvider struct {
 	masterDb  intf.DbClient
 	slaveDbs  []intf.DbClient
 	extraDbs  map[string]intf.DbClient
+	balancer  *db.Balancer[intf.DbClient]
+	router    intf.ShardRouter
 }
 
 func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.DbProvider, error) {
@@ -39,7 +46,7 @@ func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.D
 func (p *mysqlProvider) Init(args ...any) error {
 	var err error
 	if p.config.Default != nil {
-		p.defaultDb, err = newClient(p.config.Default)
+		p.defaultDb, err = newClient(p.config.Default, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql default connection")
 		}
@@ -50,7 +57,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	if p.config.Master != nil {
-		p.masterDb, err = newClient(p.config.Master)
+		p.masterDb, err = newClient(p.config.Master, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql master connection")
 		}
@@ -58,7 +65,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for i, slaveConf := range p.config.Slaves {
-		slaveClient, err := newClient(slaveConf)
+		slaveClient, err := newClient(slaveConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "init mysql slave connection, index: %d", i)
 		}
@@ -68,7 +75,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for _, itemConf := range p.config.Items {
-		itemClient, err := newClient(itemConf)
+		itemClient, err := newClient(itemConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "new mysql extra connection, name: %s", itemConf.Name)
 		}
@@ -77,6 +84,28 @@ func (p *mysqlProvider) Init(args ...any) error {
 		p.logger.Debug("init mysql extra", "name", itemConf.Name, "host", itemConf.Host)
 	}
 
+	// 读写分离, 读请求在从库间负载均衡
+	writer := p.masterDb
+	if writer == nil {
+		writer = p.defaultDb
+	}
+
+	p.balancer, err = db.NewBalancer(writer, p.slaveDbs, db.GetSlaveWeights(p.config.Slaves), p.config.ReadStrategy)
+	if err != nil {
+		return errors.Wrap(err, "init mysql balancer")
+	}
+
+	if p.config.Shard != nil {
+		p.router, err = shard.New(p.config.Shard, pie.Keys(p.extraDbs))
+		if err != nil {
+			return errors.Wrap(err, "init mysql shard router")
+		}
+
+		if err = shard.Watch(p.router, p.config.Shard, p.logger); err != nil {
+			return errors.Wrap(err, "watch mysql shard config")
+		}
+	}
+
 	return nil
 }
 
@@ -89,9 +118,43 @@ func (p *mysqlProvider) Master() intf.DbClient {
 }
 
 func (p *mysqlProvider) Slave(i int) intf.DbClient {
+	if i < 0 || i >= len(p.slaveDbs) {
+		return nil
+	}
 	return p.slaveDbs[i]
 }
 
+func (p *mysqlProvider) Reader(ctx context.Context) intf.DbClient {
+	return p.balancer.Reader(ctx)
+}
+
+func (p *mysqlProvider) Writer() intf.DbClient {
+	return p.balancer.Writer()
+}
+
 func (p *mysqlProvider) By(name string) intf.DbClient {
 	return p.extraDbs[name]
 }
+
+func (p *mysqlProvider) ForTenant(ctx context.Context) intf.DbClient {
+	if name := shard.RouteContext(ctx, p.router); name != "" {
+		return p.By(name)
+	}
+	return p.My()
+}
+
+func (p *mysqlProvider) Router() intf.ShardRouter {
+	return p.router
+}
+
+func (p *mysqlProvider) Stats() map[string]sql.DBStats {
+	return db.CollectStats(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
+
+// Close 停止从库健康检查并关闭所有连接池, sdk停止时调用
+func (p *mysqlProvider) Close() error {
+	if p.balancer != nil {
+		p.balancer.Close()
+	}
+	return db.CloseAll(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
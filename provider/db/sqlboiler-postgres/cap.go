@@ -0,0 +1,18 @@
+package sqlboiler_postgres
+
+import (
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"go.uber.org/fx"
+)
+
+var Capability = &intf.Capability{
+	Category: intf.ProviderCategoryDb,
+	Module: fx.Module(
+		string(intf.ProviderNameDbSqlBoilerPostgres),
+		fx.Provide(New),
+		fx.Invoke(func(lc fx.Lifecycle, p intf.DbProvider) {
+			db.CloseOnStop(lc, p)
+		}),
+	),
+}
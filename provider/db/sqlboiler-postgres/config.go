@@ -0,0 +1,126 @@
+package sqlboiler_postgres
+
+import (
+	"github.com/hdget/hdsdk/v2/errdef"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/pkg/errors"
+)
+
+type postgresProviderConfig struct {
+	Default *postgresConfig   `mapstructure:"default"`
+	Master  *postgresConfig   `mapstructure:"master"`
+	Slaves  []*postgresConfig `mapstructure:"slaves"`
+	Items   []*postgresConfig `mapstructure:"items"`
+	// 从库选择策略: round_robin(缺省), weighted
+	ReadStrategy string `mapstructure:"read_strategy"`
+	// 租户路由, 按租户id路由到items中的数据源
+	Shard *shard.Config `mapstructure:"shard"`
+}
+
+type postgresConfig struct {
+	Name         string `mapstructure:"name"`
+	User         string `mapstructure:"user"`
+	Password     string `mapstructure:"password"`
+	Host         string `mapstructure:"host"`
+	Port         int    `mapstructure:"port"`
+	Database     string `mapstructure:"database"`
+	Schema       string `mapstructure:"schema"`        // search_path, 为空时使用数据库缺省的schema
+	SslMode      string `mapstructure:"sslmode"`       // disable, require, verify-ca, verify-full
+	Timeout      int    `mapstructure:"timeout"`       // 连接超时时间, 单位为秒
+	QueryTimeout int    `mapstructure:"query_timeout"` // 缺省查询超时时间, 单位为秒, ctx没有设置deadline时生效
+	// 连接池, 从库权重和慢查询日志参数, 配置在实例的同一级
+	db.PoolConfig     `mapstructure:",squash"`
+	db.WeightConfig   `mapstructure:",squash"`
+	db.QueryLogConfig `mapstructure:",squash"`
+}
+
+const (
+	configSection  = "sdk.postgres"
+	defaultSslMode = "disable"
+)
+
+func newConfig(configProvider intf.ConfigProvider) (*postgresProviderConfig, error) {
+	if configProvider == nil {
+		return nil, errdef.ErrInvalidConfig
+	}
+
+	var c *postgresProviderConfig
+	err := configProvider.Unmarshal(&c, configSection)
+	if err != nil {
+		return nil, err
+	}
+
+	if c == nil {
+		return nil, errdef.ErrEmptyConfig
+	}
+
+	err = c.validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "validate postgres provider config")
+	}
+
+	return c, nil
+}
+
+func (c *postgresProviderConfig) validate() error {
+	if c.Default != nil {
+		err := c.validateInstance(c.Default)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Master != nil {
+		err := c.validateInstance(c.Master)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, slave := range c.Slaves {
+		err := c.validateInstance(slave)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range c.Items {
+		err := c.validateExtraInstance(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *postgresProviderConfig) validateInstance(ic *postgresConfig) error {
+	if ic == nil || ic.Host == "" || ic.User == "" {
+		return errdef.ErrEmptyConfig
+	}
+
+	c.setupDefault(ic)
+	return nil
+}
+
+func (c *postgresProviderConfig) validateExtraInstance(ic *postgresConfig) error {
+	if ic == nil || ic.Host == "" || ic.Name == "" {
+		return errdef.ErrEmptyConfig
+	}
+
+	c.setupDefault(ic)
+	return nil
+}
+
+// setupDefault setup default config value
+func (c *postgresProviderConfig) setupDefault(ic *postgresConfig) {
+	if ic.Port == 0 {
+		ic.Port = 5432
+	}
+
+	if ic.SslMode == "" {
+		ic.SslMode = defaultSslMode
+	}
+}
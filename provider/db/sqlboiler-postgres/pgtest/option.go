@@ -0,0 +1,55 @@
+package pgtest
+
+import "io"
+
+type Option func(*serverOption)
+
+type serverOption struct {
+	port      uint32
+	database  string
+	user      string
+	password  string
+	cachePath string    // 下载的PostgreSQL二进制包缓存目录, 为空时使用缺省目录
+	logger    io.Writer // PostgreSQL进程的输出, 为空时丢弃
+}
+
+const (
+	defaultPort     = 15432
+	defaultDatabase = "test"
+	defaultUser     = "postgres"
+	defaultPassword = "postgres"
+)
+
+func newOption() *serverOption {
+	return &serverOption{
+		port:     defaultPort,
+		database: defaultDatabase,
+		user:     defaultUser,
+		password: defaultPassword,
+		logger:   io.Discard,
+	}
+}
+
+func WithPort(port uint32) Option {
+	return func(o *serverOption) {
+		o.port = port
+	}
+}
+
+func WithDatabase(database string) Option {
+	return func(o *serverOption) {
+		o.database = database
+	}
+}
+
+func WithCachePath(cachePath string) Option {
+	return func(o *serverOption) {
+		o.cachePath = cachePath
+	}
+}
+
+func WithLogger(logger io.Writer) Option {
+	return func(o *serverOption) {
+		o.logger = logger
+	}
+}
@@ -0,0 +1,88 @@
+// Package pgtest 启动嵌入式PostgreSQL, 用于在本地测试sqlboiler-postgres provider, 不需要安装数据库
+// 首次启动时会下载PostgreSQL二进制包, PostgreSQL不能以root用户运行
+package pgtest
+
+import (
+	embeddedPostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/hdget/hdsdk/v2/intf"
+	sqlboilerPostgres "github.com/hdget/hdsdk/v2/provider/db/sqlboiler-postgres"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Server 嵌入式PostgreSQL实例
+type Server struct {
+	*serverOption
+	db *embeddedPostgres.EmbeddedPostgres
+}
+
+// configProvider 只包含sdk.postgres.default配置, 指向嵌入式实例
+type configProvider struct {
+	v *viper.Viper
+}
+
+const (
+	configSection = "sdk.postgres.default"
+)
+
+// Start 启动嵌入式PostgreSQL, 使用完后需要调用Stop
+func Start(options ...Option) (*Server, error) {
+	o := newOption()
+	for _, apply := range options {
+		apply(o)
+	}
+
+	c := embeddedPostgres.DefaultConfig().
+		Port(o.port).
+		Database(o.database).
+		Username(o.user).
+		Password(o.password).
+		Logger(o.logger)
+	if o.cachePath != "" {
+		c = c.CachePath(o.cachePath)
+	}
+
+	db := embeddedPostgres.NewDatabase(c)
+	if err := db.Start(); err != nil {
+		return nil, errors.Wrap(err, "start embedded postgres")
+	}
+
+	return &Server{serverOption: o, db: db}, nil
+}
+
+// ConfigProvider 返回sdk.postgres.default指向该实例的配置
+func (s *Server) ConfigProvider() intf.ConfigProvider {
+	v := viper.New()
+	v.Set(configSection, map[string]any{
+		"host":     "127.0.0.1",
+		"port":     s.port,
+		"user":     s.user,
+		"password": s.password,
+		"database": s.database,
+	})
+	return &configProvider{v: v}
+}
+
+// NewProvider 创建连接该实例的sqlboiler-postgres provider
+func (s *Server) NewProvider(logger intf.LoggerProvider) (intf.DbProvider, error) {
+	return sqlboilerPostgres.New(s.ConfigProvider(), logger)
+}
+
+func (s *Server) Stop() error {
+	return s.db.Stop()
+}
+
+func (p *configProvider) Unmarshal(configVar any, key ...string) error {
+	if len(key) == 0 {
+		return p.v.Unmarshal(configVar)
+	}
+	return p.v.UnmarshalKey(key[0], configVar)
+}
+
+func (p *configProvider) GetApp() string {
+	return "pgtest"
+}
+
+func (p *configProvider) GetEnv() string {
+	return "test"
+}
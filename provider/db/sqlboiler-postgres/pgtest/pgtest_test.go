@@ -0,0 +1,67 @@
+//go:build pgtest
+
+package pgtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hdget/hdsdk/v2/lib/sqlboiler"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/hdget/hdsdk/v2/provider/db/sqlboiler-postgres/pgtest"
+	"github.com/hdget/hdsdk/v2/provider/logger/zerolog"
+	"github.com/pkg/errors"
+)
+
+// TestProvider 需要下载PostgreSQL二进制包且不能以root用户运行, 通过go test -tags pgtest执行
+func TestProvider(t *testing.T) {
+	s, err := pgtest.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = s.Stop()
+	}()
+
+	logger, err := zerolog.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := s.NewProvider(logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := provider.My()
+
+	d := sqlboiler.DialectOf(client)
+	if d != sqlboiler.DialectPostgres {
+		t.Fatalf("unexpected dialect: %v", d)
+	}
+
+	var n int64
+	if err = client.Get(&n, client.Rebind("SELECT "+d.IfNull("NULL::int", 1, "n"))); err != nil || n != 1 {
+		t.Fatalf("coalesce, n: %d, err: %v", n, err)
+	}
+
+	ctx := context.Background()
+	if _, err = client.ExecContext(ctx, "CREATE TABLE t (id int PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 事务回滚后数据不可见
+	errRollback := errors.New("rollback")
+	err = db.InTx(ctx, client, func(ctx context.Context) error {
+		if _, err := db.Executor(ctx, client).ExecContext(ctx, client.Rebind("INSERT INTO t (id) VALUES (?)"), 1); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("unexpected tx error: %v", err)
+	}
+
+	if err = client.Get(&n, "SELECT COUNT(*) FROM t"); err != nil || n != 0 {
+		t.Fatalf("count after rollback, n: %d, err: %v", n, err)
+	}
+}
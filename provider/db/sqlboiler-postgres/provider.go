@@ -0,0 +1,160 @@
+package sqlboiler_postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/elliotchance/pie/v2"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+type postgresProvider struct {
+	logger    intf.LoggerProvider
+	config    *postgresProviderConfig
+	defaultDb intf.DbClient
+	masterDb  intf.DbClient
+	slaveDbs  []intf.DbClient
+	extraDbs  map[string]intf.DbClient
+	balancer  *db.Balancer[intf.DbClient]
+	router    intf.ShardRouter
+}
+
+func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.DbProvider, error) {
+	c, err := newConfig(configProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &postgresProvider{
+		logger:   logger,
+		config:   c,
+		slaveDbs: make([]intf.DbClient, len(c.Slaves)),
+		extraDbs: make(map[string]intf.DbClient),
+	}
+
+	err = provider.Init(logger, c)
+	if err != nil {
+		logger.Fatal("init postgres provider", "err", err)
+	}
+
+	return provider, nil
+}
+
+func (p *postgresProvider) Init(args ...any) error {
+	var err error
+	if p.config.Default != nil {
+		p.defaultDb, err = newClient(p.config.Default, p.logger)
+		if err != nil {
+			return errors.Wrap(err, "init postgres default connection")
+		}
+
+		// 设置boil的缺省db
+		boil.SetDB(p.defaultDb)
+		p.logger.Debug("init postgres default", "host", p.config.Default.Host)
+	}
+
+	if p.config.Master != nil {
+		p.masterDb, err = newClient(p.config.Master, p.logger)
+		if err != nil {
+			return errors.Wrap(err, "init postgres master connection")
+		}
+		p.logger.Debug("init postgres master", "host", p.config.Master.Host)
+	}
+
+	for i, slaveConf := range p.config.Slaves {
+		slaveClient, err := newClient(slaveConf, p.logger)
+		if err != nil {
+			return errors.Wrapf(err, "init postgres slave connection, index: %d", i)
+		}
+
+		p.slaveDbs[i] = slaveClient
+		p.logger.Debug("init postgres slave", "index", i, "host", slaveConf.Host)
+	}
+
+	for _, itemConf := range p.config.Items {
+		itemClient, err := newClient(itemConf, p.logger)
+		if err != nil {
+			return errors.Wrapf(err, "new postgres extra connection, name: %s", itemConf.Name)
+		}
+
+		p.extraDbs[itemConf.Name] = itemClient
+		p.logger.Debug("init postgres extra", "name", itemConf.Name, "host", itemConf.Host)
+	}
+
+	// 读写分离, 读请求在从库间负载均衡
+	writer := p.masterDb
+	if writer == nil {
+		writer = p.defaultDb
+	}
+
+	p.balancer, err = db.NewBalancer(writer, p.slaveDbs, db.GetSlaveWeights(p.config.Slaves), p.config.ReadStrategy)
+	if err != nil {
+		return errors.Wrap(err, "init postgres balancer")
+	}
+
+	if p.config.Shard != nil {
+		p.router, err = shard.New(p.config.Shard, pie.Keys(p.extraDbs))
+		if err != nil {
+			return errors.Wrap(err, "init postgres shard router")
+		}
+
+		if err = shard.Watch(p.router, p.config.Shard, p.logger); err != nil {
+			return errors.Wrap(err, "watch postgres shard config")
+		}
+	}
+
+	return nil
+}
+
+func (p *postgresProvider) My() intf.DbClient {
+	return p.defaultDb
+}
+
+func (p *postgresProvider) Master() intf.DbClient {
+	return p.masterDb
+}
+
+func (p *postgresProvider) Slave(i int) intf.DbClient {
+	if i < 0 || i >= len(p.slaveDbs) {
+		return nil
+	}
+	return p.slaveDbs[i]
+}
+
+func (p *postgresProvider) Reader(ctx context.Context) intf.DbClient {
+	return p.balancer.Reader(ctx)
+}
+
+func (p *postgresProvider) Writer() intf.DbClient {
+	return p.balancer.Writer()
+}
+
+func (p *postgresProvider) By(name string) intf.DbClient {
+	return p.extraDbs[name]
+}
+
+func (p *postgresProvider) ForTenant(ctx context.Context) intf.DbClient {
+	if name := shard.RouteContext(ctx, p.router); name != "" {
+		return p.By(name)
+	}
+	return p.My()
+}
+
+func (p *postgresProvider) Router() intf.ShardRouter {
+	return p.router
+}
+
+func (p *postgresProvider) Stats() map[string]sql.DBStats {
+	return db.CollectStats(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
+
+// Close 停止从库健康检查并关闭所有连接池, sdk停止时调用
+func (p *postgresProvider) Close() error {
+	if p.balancer != nil {
+		p.balancer.Close()
+	}
+	return db.CloseAll(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
@@ -1,9 +1,10 @@
 package sqlboiler_sqlite3
 
 import (
-	"database/sql"
 	"fmt"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
+	"github.com/jmoiron/sqlx"
 	_ "modernc.org/sqlite"
 	"os"
 	"path/filepath"
@@ -11,7 +12,7 @@ import (
 )
 
 type sqliteClient struct {
-	*sql.DB
+	*db.InstrumentedDB
 }
 
 const (
@@ -35,15 +36,15 @@ func newClient(c *sqliteProviderConfig, args ...string) (intf.DbClient, error) {
 
 	// 构造连接参数
 	dsn := fmt.Sprintf(dsnTemplate, absDbFile)
-	db, err := sql.Open("sqlite", dsn)
+	sqlDb, err := sqlx.Open("sqlite", dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	var userVersion int
-	err = db.QueryRow("PRAGMA user_version").Scan(&userVersion)
+	err = sqlDb.QueryRow("PRAGMA user_version").Scan(&userVersion)
 	if err != nil {
-		_ = db.Close()
+		_ = sqlDb.Close()
 		return nil, fmt.Errorf("fail connect db: %s", absDbFile)
 	}
 
@@ -52,23 +53,7 @@ func newClient(c *sqliteProviderConfig, args ...string) (intf.DbClient, error) {
 	// Avoid issue:
 	// packets.go:123: closing bad idle connection: EOF
 	// connection.go:173: driver: bad connection
-	db.SetConnMaxLifetime(3 * time.Minute)
+	sqlDb.SetConnMaxLifetime(3 * time.Minute)
 
-	return &sqliteClient{DB: db}, nil
-}
-
-func (m sqliteClient) Close() error {
-	return m.DB.Close()
-}
-
-func (m sqliteClient) Get(dest interface{}, query string, args ...interface{}) error {
-	return nil
-}
-
-func (m sqliteClient) Select(dest interface{}, query string, args ...interface{}) error {
-	return nil
-}
-
-func (m sqliteClient) Rebind(query string) string {
-	return ""
+	return &sqliteClient{InstrumentedDB: db.NewInstrumentedDB(sqlDb, 0)}, nil
 }
@@ -1,7 +1,10 @@
 package sqlboiler_sqlite3
 
 import (
+	"context"
+	"database/sql"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/pkg/errors"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	_ "modernc.org/sqlite"
@@ -71,6 +74,28 @@ func (p *sqliteProvider) Slave(i int) intf.DbClient {
 	return nil
 }
 
+// Reader sqlite没有从库, 始终返回缺省数据库
+func (p *sqliteProvider) Reader(ctx context.Context) intf.DbClient {
+	return p.defaultDb
+}
+
+func (p *sqliteProvider) Writer() intf.DbClient {
+	return p.defaultDb
+}
+
 func (p *sqliteProvider) By(name string) intf.DbClient {
 	return nil
 }
+
+// ForTenant sqlite只有一个数据库, 始终返回缺省数据库
+func (p *sqliteProvider) ForTenant(ctx context.Context) intf.DbClient {
+	return p.defaultDb
+}
+
+func (p *sqliteProvider) Router() intf.ShardRouter {
+	return nil
+}
+
+func (p *sqliteProvider) Stats() map[string]sql.DBStats {
+	return db.CollectStats[intf.DbClient](p.defaultDb, nil, nil, nil)
+}
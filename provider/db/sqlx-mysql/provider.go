@@ -1,7 +1,12 @@
 package sqlx_mysql
 
 import (
+	"context"
+	"database/sql"
+	"github.com/elliotchance/pie/v2"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/pkg/errors"
 )
 
@@ -12,6 +17,8 @@ type mysqlProvider struct {
 	masterDb  intf.SqlxDbClient
 	slaveDbs  []intf.SqlxDbClient
 	extraDbs  map[string]intf.SqlxDbClient
+	balancer  *db.Balancer[intf.SqlxDbClient]
+	router    intf.ShardRouter
 }
 
 func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.SqlxDbProvider, error) {
@@ -38,7 +45,7 @@ func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.S
 func (p *mysqlProvider) Init(args ...any) error {
 	var err error
 	if p.config.Default != nil {
-		p.defaultDb, err = newClient(p.config.Default)
+		p.defaultDb, err = newClient(p.config.Default, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql default connection")
 		}
@@ -47,7 +54,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	if p.config.Master != nil {
-		p.masterDb, err = newClient(p.config.Master)
+		p.masterDb, err = newClient(p.config.Master, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql master connection")
 		}
@@ -55,7 +62,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for i, slaveConf := range p.config.Slaves {
-		slaveClient, err := newClient(slaveConf)
+		slaveClient, err := newClient(slaveConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "init mysql slave connection, index: %d", i)
 		}
@@ -65,7 +72,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for _, itemConf := range p.config.Items {
-		itemClient, err := newClient(itemConf)
+		itemClient, err := newClient(itemConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "new mysql extra connection, name: %s", itemConf.Name)
 		}
@@ -74,6 +81,28 @@ func (p *mysqlProvider) Init(args ...any) error {
 		p.logger.Debug("init mysql extra", "name", itemConf.Name, "host", itemConf.Host)
 	}
 
+	// 读写分离, 读请求在从库间负载均衡
+	writer := p.masterDb
+	if writer == nil {
+		writer = p.defaultDb
+	}
+
+	p.balancer, err = db.NewBalancer(writer, p.slaveDbs, db.GetSlaveWeights(p.config.Slaves), p.config.ReadStrategy)
+	if err != nil {
+		return errors.Wrap(err, "init mysql balancer")
+	}
+
+	if p.config.Shard != nil {
+		p.router, err = shard.New(p.config.Shard, pie.Keys(p.extraDbs))
+		if err != nil {
+			return errors.Wrap(err, "init mysql shard router")
+		}
+
+		if err = shard.Watch(p.router, p.config.Shard, p.logger); err != nil {
+			return errors.Wrap(err, "watch mysql shard config")
+		}
+	}
+
 	return nil
 }
 
@@ -86,9 +115,43 @@ func (p *mysqlProvider) Master() intf.SqlxDbClient {
 }
 
 func (p *mysqlProvider) Slave(i int) intf.SqlxDbClient {
+	if i < 0 || i >= len(p.slaveDbs) {
+		return nil
+	}
 	return p.slaveDbs[i]
 }
 
+func (p *mysqlProvider) Reader(ctx context.Context) intf.SqlxDbClient {
+	return p.balancer.Reader(ctx)
+}
+
+func (p *mysqlProvider) Writer() intf.SqlxDbClient {
+	return p.balancer.Writer()
+}
+
 func (p *mysqlProvider) By(name string) intf.SqlxDbClient {
 	return p.extraDbs[name]
 }
+
+func (p *mysqlProvider) ForTenant(ctx context.Context) intf.SqlxDbClient {
+	if name := shard.RouteContext(ctx, p.router); name != "" {
+		return p.By(name)
+	}
+	return p.My()
+}
+
+func (p *mysqlProvider) Router() intf.ShardRouter {
+	return p.router
+}
+
+func (p *mysqlProvider) Stats() map[string]sql.DBStats {
+	return db.CollectStats(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
+
+// Close 停止从库健康检查并关闭所有连接池, sdk停止时调用
+func (p *mysqlProvider) Close() error {
+	if p.balancer != nil {
+		p.balancer.Close()
+	}
+	return db.CloseAll(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
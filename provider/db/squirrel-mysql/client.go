@@ -1,105 +1,160 @@
 package sqlx_mysql
 
 import (
-	"fmt"
+	"context"
 	"github.com/Masterminds/squirrel"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hdget/hdsdk/v2/intf"
 	"github.com/hdget/hdsdk/v2/lib/pagination"
 	"github.com/hdget/hdsdk/v2/protobuf"
-	"github.com/hdget/hdutils/page"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
-	"time"
 )
 
+// sqlxExecutor InstrumentedDB和绑定了事务的client都实现了该接口
+type sqlxExecutor interface {
+	intf.DbClient
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+}
+
 type mysqlClient struct {
-	*sqlx.DB
+	*db.InstrumentedDB
 	_builder intf.Sqlizer
 }
 
-const (
-	// 这里设置解析时间类型https://github.com/go-sql-driver/mysql#timetime-support
-	// DSN (Data Type NickName): username:password@protocol(address)/dbname?param=value
-	dsnTemplate = "%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local"
-)
+func newDb(c *mysqlConfig, logger intf.LoggerProvider) (*db.InstrumentedDB, error) {
+	// 构造连接参数, 解析时间类型参考https://github.com/go-sql-driver/mysql#timetime-support
+	dsn, err := db.GetMysqlDsn(c.User, c.Password, c.Host, c.Port, c.Database, c.Timeout, c.MysqlDsnConfig)
+	if err != nil {
+		return nil, err
+	}
 
-func newDb(c *mysqlConfig) (*sqlx.DB, error) {
-	// 构造连接参数
-	dsn := fmt.Sprintf(dsnTemplate, c.User, c.Password, c.Host, c.Port, c.Database)
-	db, err := sqlx.Connect("mysql", dsn)
+	sqlDb, err := sqlx.Connect("mysql", dsn)
 	if err != nil {
 		return nil, err
 	}
-	// https://www.alexedwards.net/blog/configuring-sqldb
-	// https://making.pusher.com/production-ready-connection-pooling-in-go
-	// Avoid issue:
-	// packets.go:123: closing bad idle connection: EOF
-	// connection.go:173: driver: bad connection
-	db.SetConnMaxLifetime(3 * time.Minute)
+	c.PoolConfig.Apply(sqlDb.DB)
 
-	return db, nil
+	return db.NewInstrumentedDB(sqlDb, c.QueryTimeout, db.NewQueryLogHooks(logger, sqlDb, c.QueryLogConfig)...), nil
 }
 
 func (m mysqlClient) ToSql() (string, []any, error) {
 	return m._builder.ToSql()
 }
 
-func (m mysqlClient) XGet(v any) error {
-	xquery, xargs, err := m._builder.ToSql()
+func (m mysqlClient) XGet(ctx context.Context, v any) error {
+	xquery, xargs, err := toSql(ctx, m._builder)
 	if err != nil {
 		return err
 	}
 
-	return m.DB.Get(v, xquery, xargs...)
+	return m.executor(ctx).GetContext(ctx, v, xquery, xargs...)
 }
 
-func (m mysqlClient) XSelect(v any, args ...*protobuf.ListParam) error {
-	bd := m._builder
-	if len(args) > 0 {
-		selBd, ok := bd.(squirrel.SelectBuilder)
-		if !ok {
-			return errors.New("invalid select builder")
-		}
-
-		p := pagination.New(args[0])
-		bd = selBd.Offset(p.Offset).Limit(p.PageSize)
+func (m mysqlClient) XSelect(ctx context.Context, v any, args ...*protobuf.ListParam) error {
+	bd, err := m.paginate(args...)
+	if err != nil {
+		return err
 	}
 
-	xquery, xargs, err := bd.ToSql()
+	xquery, xargs, err := toSql(ctx, bd)
 	if err != nil {
 		return err
 	}
-	return m.DB.Select(v, xquery, xargs...)
+	return m.executor(ctx).SelectContext(ctx, v, xquery, xargs...)
 }
 
-func (m mysqlClient) XCount() (int64, error) {
-	xquery, xargs, err := m._builder.ToSql()
+func (m mysqlClient) XSelectKeyset(ctx context.Context, v any, ks *pagination.Keyset) (*pagination.KeysetPage, error) {
+	selBd, ok := m._builder.(squirrel.SelectBuilder)
+	if !ok {
+		return nil, errors.New("invalid select builder")
+	}
+
+	if where, args := ks.Where(); where != "" {
+		selBd = selBd.Where(where, args...)
+	}
+	selBd = selBd.OrderBy(ks.OrderBy()).Limit(uint64(ks.Limit()))
+
+	xquery, xargs, err := selBd.ToSql()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	var total int64
-	err = m.DB.Get(&total, xquery, xargs...)
-	return total, err
+	if err = m.executor(ctx).SelectContext(ctx, v, xquery, xargs...); err != nil {
+		return nil, err
+	}
+	return ks.Paginate(v)
+}
+
+// XCount 直接执行sqlizer读取总数, sqlizer需要是COUNT语句, e,g: squirrel.Select("COUNT(*)").From("user")
+func (m mysqlClient) XCount(ctx context.Context) (int64, error) {
+	return m.count(ctx, m._builder)
 }
 
-func (m mysqlClient) XQuery(args ...*protobuf.ListParam) (*sqlx.Rows, error) {
-	var p page.Pagination
-	bd := m._builder
-	if len(args) > 0 {
-		selBd, ok := bd.(squirrel.SelectBuilder)
-		if !ok {
-			return nil, errors.New("invalid select builder")
-		}
+// XCountDerived SelectBuilder去掉Limit和Offset后作为子查询计算总数, 支持GROUP BY和DISTINCT
+// 用于同一个列表查询的builder同时计算总数, 需要使用加OrderBy之前的builder, 子查询中的排序对总数没有影响但是会增加开销
+// builder本身是COUNT语句时使用XCount
+func (m mysqlClient) XCountDerived(ctx context.Context) (int64, error) {
+	selBd, ok := m._builder.(squirrel.SelectBuilder)
+	if !ok {
+		return 0, errors.New("invalid select builder")
+	}
+
+	return m.count(ctx, squirrel.Select("COUNT(*)").FromSelect(selBd.RemoveLimit().RemoveOffset(), "t"))
+}
 
-		p = pagination.New(args[0])
-		bd = selBd.Offset(p.Offset).Limit(p.PageSize)
+func (m mysqlClient) XQuery(ctx context.Context, args ...*protobuf.ListParam) (*sqlx.Rows, error) {
+	bd, err := m.paginate(args...)
+	if err != nil {
+		return nil, err
 	}
 
-	xquery, xargs, err := bd.ToSql()
+	xquery, xargs, err := toSql(ctx, bd)
 	if err != nil {
 		return nil, err
 	}
-	return m.DB.Queryx(xquery, xargs...)
+	return m.executor(ctx).QueryxContext(ctx, xquery, xargs...)
+}
+
+// paginate 有分页参数时builder必须为SelectBuilder
+func (m mysqlClient) paginate(args ...*protobuf.ListParam) (intf.Sqlizer, error) {
+	if len(args) == 0 {
+		return m._builder, nil
+	}
+
+	selBd, ok := m._builder.(squirrel.SelectBuilder)
+	if !ok {
+		return nil, errors.New("invalid select builder")
+	}
+
+	p := pagination.New(args[0])
+	return selBd.Offset(p.Offset).Limit(p.PageSize), nil
+}
+
+func (m mysqlClient) count(ctx context.Context, bd intf.Sqlizer) (int64, error) {
+	xquery, xargs, err := toSql(ctx, bd)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = m.executor(ctx).GetContext(ctx, &total, xquery, xargs...)
+	return total, err
+}
+
+// toSql sqlizer实现了intf.ContextSqlizer时使用ctx生成SQL, e,g: sqltemplate根据ctx生成租户条件
+func toSql(ctx context.Context, sqlizer intf.Sqlizer) (string, []any, error) {
+	if v, ok := sqlizer.(intf.ContextSqlizer); ok {
+		return v.ToSqlContext(ctx)
+	}
+	return sqlizer.ToSql()
+}
+
+// executor ctx中有db.InTx开启的事务时返回绑定了事务的client
+func (m mysqlClient) executor(ctx context.Context) sqlxExecutor {
+	if exec, ok := db.Executor(ctx, m.InstrumentedDB).(sqlxExecutor); ok {
+		return exec
+	}
+	return m.InstrumentedDB
 }
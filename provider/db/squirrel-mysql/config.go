@@ -3,6 +3,8 @@ package sqlx_mysql
 import (
 	"github.com/hdget/hdsdk/v2/errdef"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/pkg/errors"
 )
 
@@ -11,16 +13,26 @@ type mysqlProviderConfig struct {
 	Master  *mysqlConfig   `mapstructure:"master"`
 	Slaves  []*mysqlConfig `mapstructure:"slaves"`
 	Items   []*mysqlConfig `mapstructure:"items"`
+	// 从库选择策略: round_robin(缺省), weighted
+	ReadStrategy string `mapstructure:"read_strategy"`
+	// 租户路由, 按租户id路由到items中的数据源
+	Shard *shard.Config `mapstructure:"shard"`
 }
 
 type mysqlConfig struct {
-	Name     string `mapstructure:"name"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Database string `mapstructure:"database"`
-	Timeout  int    `mapstructure:"timeout"`
+	Name         string `mapstructure:"name"`
+	User         string `mapstructure:"user"`
+	Password     string `mapstructure:"password"`
+	Host         string `mapstructure:"host"`
+	Port         int    `mapstructure:"port"`
+	Database     string `mapstructure:"database"`
+	Timeout      int    `mapstructure:"timeout"`
+	QueryTimeout int    `mapstructure:"query_timeout"` // 缺省查询超时时间, 单位为秒, ctx没有设置deadline时生效
+	// 连接池, 从库权重, DSN和慢查询日志参数, 配置在实例的同一级
+	db.PoolConfig     `mapstructure:",squash"`
+	db.WeightConfig   `mapstructure:",squash"`
+	db.MysqlDsnConfig `mapstructure:",squash"`
+	db.QueryLogConfig `mapstructure:",squash"`
 }
 
 const (
@@ -1,18 +1,24 @@
 package sqlx_mysql
 
 import (
+	"context"
+	"database/sql"
+	"github.com/elliotchance/pie/v2"
 	"github.com/hdget/hdsdk/v2/intf"
-	"github.com/jmoiron/sqlx"
+	"github.com/hdget/hdsdk/v2/lib/shard"
+	"github.com/hdget/hdsdk/v2/provider/db"
 	"github.com/pkg/errors"
 )
 
 type mysqlProvider struct {
 	logger    intf.LoggerProvider
 	config    *mysqlProviderConfig
-	defaultDb *sqlx.DB
-	masterDb  *sqlx.DB
-	slaveDbs  []*sqlx.DB
-	extraDbs  map[string]*sqlx.DB
+	defaultDb *db.InstrumentedDB
+	masterDb  *db.InstrumentedDB
+	slaveDbs  []*db.InstrumentedDB
+	extraDbs  map[string]*db.InstrumentedDB
+	balancer  *db.Balancer[*db.InstrumentedDB]
+	router    intf.ShardRouter
 	_builder  intf.Sqlizer
 }
 
@@ -25,8 +31,8 @@ func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.D
 	provider := &mysqlProvider{
 		logger:   logger,
 		config:   c,
-		slaveDbs: make([]*sqlx.DB, len(c.Slaves)),
-		extraDbs: make(map[string]*sqlx.DB),
+		slaveDbs: make([]*db.InstrumentedDB, len(c.Slaves)),
+		extraDbs: make(map[string]*db.InstrumentedDB),
 	}
 
 	err = provider.Init(logger, c)
@@ -40,7 +46,7 @@ func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.D
 func (p *mysqlProvider) Init(args ...any) error {
 	var err error
 	if p.config.Default != nil {
-		p.defaultDb, err = newDb(p.config.Default)
+		p.defaultDb, err = newDb(p.config.Default, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql default connection")
 		}
@@ -49,7 +55,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	if p.config.Master != nil {
-		p.masterDb, err = newDb(p.config.Master)
+		p.masterDb, err = newDb(p.config.Master, p.logger)
 		if err != nil {
 			return errors.Wrap(err, "init mysql master connection")
 		}
@@ -57,7 +63,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for i, slaveConf := range p.config.Slaves {
-		slaveClient, err := newDb(slaveConf)
+		slaveClient, err := newDb(slaveConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "init mysql slave connection, index: %d", i)
 		}
@@ -67,7 +73,7 @@ func (p *mysqlProvider) Init(args ...any) error {
 	}
 
 	for _, itemConf := range p.config.Items {
-		itemClient, err := newDb(itemConf)
+		itemClient, err := newDb(itemConf, p.logger)
 		if err != nil {
 			return errors.Wrapf(err, "new mysql extra connection, name: %s", itemConf.Name)
 		}
@@ -76,37 +82,114 @@ func (p *mysqlProvider) Init(args ...any) error {
 		p.logger.Debug("init mysql extra", "name", itemConf.Name, "host", itemConf.Host)
 	}
 
+	// 读写分离, 读请求在从库间负载均衡
+	writer := p.masterDb
+	if writer == nil {
+		writer = p.defaultDb
+	}
+
+	p.balancer, err = db.NewBalancer(writer, p.slaveDbs, db.GetSlaveWeights(p.config.Slaves), p.config.ReadStrategy)
+	if err != nil {
+		return errors.Wrap(err, "init mysql balancer")
+	}
+
+	if p.config.Shard != nil {
+		p.router, err = shard.New(p.config.Shard, pie.Keys(p.extraDbs))
+		if err != nil {
+			return errors.Wrap(err, "init mysql shard router")
+		}
+
+		if err = shard.Watch(p.router, p.config.Shard, p.logger); err != nil {
+			return errors.Wrap(err, "watch mysql shard config")
+		}
+	}
+
 	return nil
 }
 
 func (p *mysqlProvider) My() intf.DbBuilderClient {
 	return &mysqlClient{
-		DB:       p.defaultDb,
-		_builder: p._builder,
+		InstrumentedDB: p.defaultDb,
+		_builder:       p._builder,
 	}
 }
 
+// Master 没有配置master时返回nil
 func (p *mysqlProvider) Master() intf.DbBuilderClient {
+	if p.masterDb == nil {
+		return nil
+	}
+
 	return &mysqlClient{
-		DB:       p.masterDb,
-		_builder: p._builder,
+		InstrumentedDB: p.masterDb,
+		_builder:       p._builder,
 	}
 }
 
 func (p *mysqlProvider) Slave(i int) intf.DbBuilderClient {
+	if i < 0 || i >= len(p.slaveDbs) {
+		return nil
+	}
+
 	return &mysqlClient{
-		DB:       p.slaveDbs[i],
-		_builder: p._builder,
+		InstrumentedDB: p.slaveDbs[i],
+		_builder:       p._builder,
 	}
 }
 
+func (p *mysqlProvider) Reader(ctx context.Context) intf.DbBuilderClient {
+	return &mysqlClient{
+		InstrumentedDB: p.balancer.Reader(ctx),
+		_builder:       p._builder,
+	}
+}
+
+func (p *mysqlProvider) Writer() intf.DbBuilderClient {
+	return &mysqlClient{
+		InstrumentedDB: p.balancer.Writer(),
+		_builder:       p._builder,
+	}
+}
+
+// By 没有名字为name的数据源时返回nil
 func (p *mysqlProvider) By(name string) intf.DbBuilderClient {
+	extraDb, exists := p.extraDbs[name]
+	if !exists || extraDb == nil {
+		return nil
+	}
+
 	return &mysqlClient{
-		DB:       p.extraDbs[name],
-		_builder: p._builder,
+		InstrumentedDB: extraDb,
+		_builder:       p._builder,
 	}
 }
 
-func (p *mysqlProvider) Set(builder intf.Sqlizer) {
-	p._builder = builder
+func (p *mysqlProvider) ForTenant(ctx context.Context) intf.DbBuilderClient {
+	if name := shard.RouteContext(ctx, p.router); name != "" {
+		return p.By(name)
+	}
+	return p.My()
+}
+
+func (p *mysqlProvider) Router() intf.ShardRouter {
+	return p.router
+}
+
+// With 浅拷贝provider, 连接池等共享, 只有绑定的builder不同
+func (p *mysqlProvider) With(builder intf.Sqlizer) intf.DbBuilderProvider {
+	scoped := *p
+	scoped._builder = builder
+	return &scoped
+}
+
+func (p *mysqlProvider) Stats() map[string]sql.DBStats {
+	return db.CollectStats(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
+}
+
+// Close 停止从库健康检查并关闭所有连接池, sdk停止时调用
+func (p *mysqlProvider) Close() error {
+	if p.balancer != nil {
+		p.balancer.Close()
+	}
+	return db.CloseAll(p.defaultDb, p.masterDb, p.slaveDbs, p.extraDbs)
 }
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// TxFunc 事务中执行的函数, 需要使用Executor(ctx, client)获取绑定了事务的client
+type TxFunc func(ctx context.Context) error
+
+type TxOption func(*txOption)
+
+type txOption struct {
+	txOptions    *sql.TxOptions
+	maxRetries   int           // 死锁时整个事务的最大重试次数
+	retryBackoff time.Duration // 重试等待时间, 按重试次数线性增长
+}
+
+type ctxKeyTx struct{}
+
+// txState 保存在ctx中的事务状态, 嵌套的InTx共享同一个txState
+type txState struct {
+	tx              *sqlx.Tx
+	instrumentation *instrumentation // 开启事务的client的超时时间和查询hook
+	mutex           sync.Mutex
+	savepoints      int
+	hooks           []func()
+}
+
+// txClient 绑定了事务的DbClient, 可以用于sqlboiler, sqlx和sqltemplate
+// 查询和开启事务的InstrumentedDB一样会应用缺省超时时间并经过查询hook
+type txClient struct {
+	*sqlx.Tx
+	*instrumentation
+}
+
+// beginner 实现了该接口的DbClient才能开启事务, provider中的client都嵌入了InstrumentedDB
+type beginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 50 * time.Millisecond
+
+	mysqlErrDeadlock            = 1213
+	postgresErrDeadlock         = "40P01"
+	postgresErrSerializeFailure = "40001"
+	savepointNameTemplate       = "sp_%d"
+)
+
+var (
+	_ intf.DbClient = (*txClient)(nil)
+)
+
+func WithTxOptions(opts *sql.TxOptions) TxOption {
+	return func(o *txOption) {
+		o.txOptions = opts
+	}
+}
+
+// WithTxMaxRetries 死锁时的最大重试次数, 0表示不重试
+func WithTxMaxRetries(maxRetries int) TxOption {
+	return func(o *txOption) {
+		if maxRetries >= 0 {
+			o.maxRetries = maxRetries
+		}
+	}
+}
+
+func WithTxRetryBackoff(backoff time.Duration) TxOption {
+	return func(o *txOption) {
+		if backoff > 0 {
+			o.retryBackoff = backoff
+		}
+	}
+}
+
+// InTx 在事务中执行fn, 事务保存在传给fn的ctx中
+// 如果ctx中已经有事务, 则复用外层事务, 并使用SAVEPOINT实现部分回滚, fn返回错误时只回滚到SAVEPOINT
+// 最外层事务遇到死锁时会重新执行整个fn, 所以fn必须可以重复执行
+// 最外层事务提交成功后按注册顺序执行AfterCommit注册的函数
+func InTx(ctx context.Context, client intf.DbClient, fn TxFunc, options ...TxOption) error {
+	if state := getTxState(ctx); state != nil {
+		return state.runNested(ctx, fn)
+	}
+
+	b, ok := client.(beginner)
+	if !ok {
+		return errors.New("db client does not support transaction")
+	}
+
+	o := &txOption{
+		maxRetries:   defaultTxMaxRetries,
+		retryBackoff: defaultTxRetryBackoff,
+	}
+	for _, apply := range options {
+		apply(o)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var hooks []func()
+		hooks, err = runTx(ctx, b, o.txOptions, fn)
+		if err == nil {
+			// 同一个请求中之后的读请求走主库
+			MarkWritten(ctx)
+			for _, hook := range hooks {
+				hook()
+			}
+			return nil
+		}
+
+		if attempt >= o.maxRetries || !isRetryableTxError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "retry transaction")
+		case <-time.After(time.Duration(attempt+1) * o.retryBackoff):
+		}
+	}
+}
+
+// Executor ctx中有事务时返回绑定了事务的client, 否则返回client本身
+func Executor(ctx context.Context, client intf.DbClient) intf.DbClient {
+	if state := getTxState(ctx); state != nil {
+		return &txClient{Tx: state.tx, instrumentation: state.instrumentation}
+	}
+	return client
+}
+
+// TxFromContext 获取ctx中的事务, 没有事务时返回nil
+func TxFromContext(ctx context.Context) *sqlx.Tx {
+	if state := getTxState(ctx); state != nil {
+		return state.tx
+	}
+	return nil
+}
+
+// AfterCommit 注册事务提交后执行的函数, ctx中没有事务时立即执行
+// 在SAVEPOINT中注册的函数, 如果SAVEPOINT被回滚则不会执行
+func AfterCommit(ctx context.Context, hook func()) {
+	state := getTxState(ctx)
+	if state == nil {
+		hook()
+		return
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	state.hooks = append(state.hooks, hook)
+}
+
+func runTx(ctx context.Context, b beginner, opts *sql.TxOptions, fn TxFunc) (hooks []func(), err error) {
+	tx, err := b.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin transaction")
+	}
+
+	state := &txState{tx: tx, instrumentation: &instrumentation{}}
+	if v, ok := b.(instrumented); ok {
+		state.instrumentation = v.getInstrumentation()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, ctxKeyTx{}, state))
+	if err != nil {
+		if e := tx.Rollback(); e != nil {
+			return nil, errors.Wrapf(err, "rollback: %v", e)
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit transaction")
+	}
+	return state.hooks, nil
+}
+
+func (s *txState) runNested(ctx context.Context, fn TxFunc) (err error) {
+	s.mutex.Lock()
+	s.savepoints++
+	savepoint := fmt.Sprintf(savepointNameTemplate, s.savepoints)
+	numHooks := len(s.hooks)
+	s.mutex.Unlock()
+
+	if _, err = s.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrapf(err, "create savepoint, name: %s", savepoint)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.rollbackTo(ctx, savepoint, numHooks)
+			panic(r)
+		}
+	}()
+
+	err = fn(ctx)
+	if err != nil {
+		if e := s.rollbackTo(ctx, savepoint, numHooks); e != nil {
+			return errors.Wrapf(err, "rollback to savepoint: %v", e)
+		}
+		return err
+	}
+
+	if _, err = s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrapf(err, "release savepoint, name: %s", savepoint)
+	}
+	return nil
+}
+
+// rollbackTo 回滚到SAVEPOINT, 同时丢弃SAVEPOINT之后注册的AfterCommit函数
+func (s *txState) rollbackTo(ctx context.Context, savepoint string, numHooks int) error {
+	s.mutex.Lock()
+	s.hooks = s.hooks[:numHooks]
+	s.mutex.Unlock()
+
+	_, err := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+	return err
+}
+
+func getTxState(ctx context.Context) *txState {
+	if ctx == nil {
+		return nil
+	}
+	state, _ := ctx.Value(ctxKeyTx{}).(*txState)
+	return state
+}
+
+// isRetryableTxError 死锁或者序列化失败时可以重试整个事务
+func isRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == postgresErrDeadlock || pqErr.Code == postgresErrSerializeFailure
+	}
+	return false
+}
+
+// Close 事务由InTx负责提交或回滚
+func (c *txClient) Close() error {
+	return nil
+}
+
+func (c *txClient) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("nested transaction should use InTx")
+}
+
+func (c *txClient) Exec(query string, args ...any) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *txClient) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *txClient) QueryRow(query string, args ...any) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+func (c *txClient) Queryx(query string, args ...any) (*sqlx.Rows, error) {
+	return c.QueryxContext(context.Background(), query, args...)
+}
+
+func (c *txClient) Get(dest any, query string, args ...any) error {
+	return c.GetContext(context.Background(), dest, query, args...)
+}
+
+func (c *txClient) Select(dest any, query string, args ...any) error {
+	return c.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (c *txClient) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.exec(ctx, c.Tx, query, args...)
+}
+
+func (c *txClient) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.query(ctx, c.Tx, query, args...)
+}
+
+func (c *txClient) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return c.queryx(ctx, c.Tx, query, args...)
+}
+
+func (c *txClient) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return c.queryRow(ctx, c.Tx, query, args...)
+}
+
+func (c *txClient) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.get(ctx, c.Tx, dest, query, args...)
+}
+
+func (c *txClient) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.selectRows(ctx, c.Tx, dest, query, args...)
+}
@@ -0,0 +1,199 @@
+package redigo
+
+import (
+	"fmt"
+	"github.com/gomodule/redigo/redis"
+	"github.com/hdget/hdsdk/v2/intf"
+	libRedis "github.com/hdget/hdsdk/v2/lib/redis"
+	"github.com/pkg/errors"
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+// bitmapBloom 基于SETBIT/GETBIT实现的布隆过滤器, 在redis没有加载RedisBloom模块时使用
+// 过滤器的位数和哈希函数个数保存在<key>:meta中, 位图保存在<key>中
+type bitmapBloom struct {
+	client intf.RedisClient
+	size   uint64 // 默认位数
+	hashes uint64 // 默认哈希函数个数
+}
+
+type redisBloomConfig struct {
+	Size   uint64 `mapstructure:"size"`   // 自动创建过滤器时的位数, 默认按100万容量1%误判率计算
+	Hashes uint64 `mapstructure:"hashes"` // 自动创建过滤器时的哈希函数个数
+}
+
+const (
+	defaultBloomCapacity  = 1000000
+	defaultBloomErrorRate = 0.01
+	bloomMaxSize          = math.MaxUint32 // redis位图最大长度
+	bloomModuleName       = "bf"
+	bloomMetaKeySuffix    = ":meta"
+)
+
+const (
+	// KEYS[1]: 位图key, KEYS[2]: meta key
+	// ARGV[1]: 默认位数, ARGV[2]: 默认哈希函数个数, ARGV[3..n]: 每个元素的两个基础哈希值
+	luaBloomAdd = `
+local meta = redis.call('HMGET', KEYS[2], 'size', 'hashes')
+local size, hashes = tonumber(meta[1]), tonumber(meta[2])
+if not size then
+	size, hashes = tonumber(ARGV[1]), tonumber(ARGV[2])
+	redis.call('HSET', KEYS[2], 'size', size, 'hashes', hashes)
+end
+local result = {}
+for i = 3, #ARGV, 2 do
+	local h1, h2 = tonumber(ARGV[i]), tonumber(ARGV[i+1])
+	local added = 0
+	for j = 0, hashes - 1 do
+		if redis.call('SETBIT', KEYS[1], (h1 + j * h2) % size, 1) == 0 then
+			added = 1
+		end
+	end
+	table.insert(result, added)
+end
+return result
+`
+	// KEYS[1]: 位图key, KEYS[2]: meta key
+	// ARGV[1..n]: 每个元素的两个基础哈希值
+	luaBloomExists = `
+local meta = redis.call('HMGET', KEYS[2], 'size', 'hashes')
+local size, hashes = tonumber(meta[1]), tonumber(meta[2])
+local result = {}
+for i = 1, #ARGV, 2 do
+	local exists = 0
+	if size then
+		local h1, h2 = tonumber(ARGV[i]), tonumber(ARGV[i+1])
+		exists = 1
+		for j = 0, hashes - 1 do
+			if redis.call('GETBIT', KEYS[1], (h1 + j * h2) % size) == 0 then
+				exists = 0
+				break
+			end
+		end
+	end
+	table.insert(result, exists)
+end
+return result
+`
+	// KEYS[1]: 位图key, KEYS[2]: meta key
+	// ARGV[1]: 位数, ARGV[2]: 哈希函数个数
+	luaBloomReserve = `
+if redis.call('EXISTS', KEYS[1]) == 1 or redis.call('EXISTS', KEYS[2]) == 1 then
+	return redis.error_reply('ERR item exists')
+end
+redis.call('HSET', KEYS[2], 'size', ARGV[1], 'hashes', ARGV[2])
+return 'OK'
+`
+)
+
+var (
+	scriptBloomAdd     = libRedis.NewScript(2, luaBloomAdd)
+	scriptBloomExists  = libRedis.NewScript(2, luaBloomExists)
+	scriptBloomReserve = libRedis.NewScript(2, luaBloomReserve)
+)
+
+func newBitmapBloom(client intf.RedisClient, conf *redisBloomConfig) *bitmapBloom {
+	size, hashes := getBloomParameters(defaultBloomErrorRate, defaultBloomCapacity)
+	if conf != nil && conf.Size > 0 {
+		size = min(conf.Size, bloomMaxSize)
+	}
+	if conf != nil && conf.Hashes > 0 {
+		hashes = conf.Hashes
+	}
+
+	return &bitmapBloom{
+		client: client,
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+func (b *bitmapBloom) reserve(key string, errorRate float64, capacity uint64) error {
+	if errorRate <= 0 || errorRate >= 1 || capacity == 0 {
+		return errors.New("invalid error rate or capacity")
+	}
+
+	size, hashes := getBloomParameters(errorRate, capacity)
+	_, err := scriptBloomReserve.Run(b.client, b.getKeys(key), size, hashes)
+	return err
+}
+
+func (b *bitmapBloom) addMulti(key string, items []any) ([]int64, error) {
+	args := []any{b.size, b.hashes}
+	return libRedis.Int64s(scriptBloomAdd.Run(b.client, b.getKeys(key), append(args, getBloomHashes(items)...)...))
+}
+
+func (b *bitmapBloom) existsMulti(key string, items []any) ([]int64, error) {
+	return libRedis.Int64s(scriptBloomExists.Run(b.client, b.getKeys(key), getBloomHashes(items)...))
+}
+
+func (b *bitmapBloom) getKeys(key string) []any {
+	return []any{key, key + bloomMetaKeySuffix}
+}
+
+// getBloomParameters 根据误判率和容量计算位数和哈希函数个数
+func getBloomParameters(errorRate float64, capacity uint64) (uint64, uint64) {
+	size := math.Ceil(-float64(capacity) * math.Log(errorRate) / (math.Ln2 * math.Ln2))
+	hashes := math.Max(1, math.Round(size/float64(capacity)*math.Ln2))
+	return uint64(math.Min(size, bloomMaxSize)), uint64(hashes)
+}
+
+// getBloomHashes 为每个元素计算两个32位的基础哈希值, 第i个哈希函数为h1+i*h2(double hashing)
+// 使用32位是为了保证在lua中计算时不会丢失精度
+func getBloomHashes(items []any) []any {
+	hashes := make([]any, 0, 2*len(items))
+	for _, item := range items {
+		h := fnv.New128a()
+		_, _ = h.Write(toBloomItem(item))
+		sum := h.Sum(nil)
+
+		h1 := uint32(sum[0])<<24 | uint32(sum[1])<<16 | uint32(sum[2])<<8 | uint32(sum[3])
+		h2 := uint32(sum[8])<<24 | uint32(sum[9])<<16 | uint32(sum[10])<<8 | uint32(sum[11])
+		hashes = append(hashes, h1, h2|1)
+	}
+	return hashes
+}
+
+// toBloomItem 与redigo参数格式化保持一致, 保证和BF.*命令中同一个元素的表示相同
+func toBloomItem(item any) []byte {
+	switch v := item.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
+
+// hasBloomModule 通过MODULE LIST检查是否加载了RedisBloom模块, 命令不可用时视为未加载
+func hasBloomModule(pool *redis.Pool) bool {
+	conn := pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	modules, err := redis.Values(conn.Do("MODULE", "LIST"))
+	if err != nil {
+		return false
+	}
+
+	// 每个模块的信息为name, <name>, ver, <ver>...
+	for _, module := range modules {
+		fields, err := redis.Values(module, nil)
+		if err != nil {
+			continue
+		}
+
+		for i := 0; i+1 < len(fields); i += 2 {
+			field, _ := redis.String(fields[i], nil)
+			value, _ := redis.String(fields[i+1], nil)
+			if field == "name" && strings.EqualFold(value, bloomModuleName) {
+				return true
+			}
+		}
+	}
+	return false
+}
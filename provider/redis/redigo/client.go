@@ -5,19 +5,61 @@ import (
 	"github.com/gomodule/redigo/redis"
 	"github.com/hdget/hdsdk/v2/intf"
 	"github.com/hdget/hdsdk/v2/lib/pagination"
+	libRedis "github.com/hdget/hdsdk/v2/lib/redis"
 	"github.com/hdget/hdsdk/v2/protobuf"
 	"github.com/hdget/hdutils/convert"
+	"github.com/pkg/errors"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type redisClient struct {
-	pool *redis.Pool
+	pool     *redis.Pool
+	prefix   string       // 所有key的前缀, 由prefixConn自动添加
+	bloom    *bitmapBloom // 没有RedisBloom模块时使用位图实现的布隆过滤器
+	replicas *replicaSet  // 从库, 只读命令路由到从库
 }
 
-func newRedisClient(conf *redisClientConfig) (intf.RedisClient, error) {
+const (
+	readTimeout = 3 * time.Second
+)
+
+func newRedisClient(conf *redisClientConfig, logger intf.LoggerProvider) (intf.RedisClient, error) {
+	p := newPool(conf.Host, conf.Port, conf.Password, conf.Db, conf.KeyPrefix)
+
+	// ping test
+	client := &redisClient{pool: p, prefix: conf.KeyPrefix}
+	err := client.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	// 预加载所有已注册的lua脚本, 失败时不影响使用, 执行时遇到NOSCRIPT会回退到EVAL
+	if err = libRedis.LoadScripts(client); err != nil {
+		logger.Warn("preload redis lua scripts", "host", conf.Host, "err", err)
+	}
+
+	// 没有加载RedisBloom模块时, BF.*命令不可用, 自动切换到位图实现
+	if !hasBloomModule(p) {
+		client.bloom = newBitmapBloom(client, conf.Bloom)
+	}
+
+	// 配置了从库时, 只读命令路由到从库
+	if len(conf.Replicas) > 0 {
+		client.replicas, err = newReplicaSet(conf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+func newPool(host string, port int, password string, db int, keyPrefix string) *redis.Pool {
 	// 建立连接池
-	p := &redis.Pool{
+	return &redis.Pool{
 		// 最大空闲连接数，有这么多个连接提前等待着，但过了超时时间也会关闭
 		MaxIdle: 256,
 		// 最大连接数，即最多的tcp连接数
@@ -27,16 +69,20 @@ func newRedisClient(conf *redisClientConfig) (intf.RedisClient, error) {
 		// 超过最大连接，是报错，还是等待
 		Wait: true,
 		Dial: func() (redis.Conn, error) {
-			conn, err := redis.Dial("tcp", fmt.Sprintf("%s:%d", conf.Host, conf.Port),
-				redis.DialPassword(conf.Password),
-				redis.DialDatabase(conf.Db),
+			conn, err := redis.Dial("tcp", fmt.Sprintf("%s:%d", host, port),
+				redis.DialPassword(password),
+				redis.DialDatabase(db),
 				redis.DialConnectTimeout(1*time.Minute),
-				redis.DialReadTimeout(3*time.Second),
+				redis.DialReadTimeout(readTimeout),
 				redis.DialWriteTimeout(30*time.Second),
 			)
 			if err != nil {
 				return nil, err
 			}
+
+			if keyPrefix != "" {
+				return newPrefixConn(conn, keyPrefix), nil
+			}
 			return conn, nil
 		},
 		TestOnBorrow: func(c redis.Conn, t time.Time) error {
@@ -47,15 +93,29 @@ func newRedisClient(conf *redisClientConfig) (intf.RedisClient, error) {
 			return err
 		},
 	}
+}
 
-	// ping test
-	client := &redisClient{pool: p}
-	err := client.Ping()
-	if err != nil {
-		return nil, err
+// Primary 返回所有命令都在主库执行的client, 用于写后立即读等不能容忍复制延迟的场景
+func (r *redisClient) Primary() intf.RedisClient {
+	if r.replicas == nil {
+		return r
 	}
 
-	return client, nil
+	primary := *r
+	primary.replicas = nil
+	return &primary
+}
+
+// readPool 只读命令使用的连接池, 没有可用的从库时使用主库
+func (r *redisClient) readPool() *redis.Pool {
+	if r.replicas == nil {
+		return r.pool
+	}
+
+	if p := r.replicas.pick(); p != nil {
+		return p
+	}
+	return r.pool
 }
 
 ///////////////////////////////////////////////////////////////////////
@@ -95,7 +155,7 @@ func (r *redisClient) Dels(keys []string) error {
 
 // Exists 检查某个key是否存在
 func (r *redisClient) Exists(key string) (bool, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -115,7 +175,7 @@ func (r *redisClient) Expire(key string, expire int) error {
 
 // Ttl 获取某个key的过期时间
 func (r *redisClient) Ttl(key string) (int64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -164,6 +224,33 @@ func (r *redisClient) Ping() error {
 	return err
 }
 
+// MGet 一次获取多个key的值, 不存在的key对应的值为nil
+func (r *redisClient) MGet(keys ...string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.ByteSlices(conn.Do("MGET", redis.Args{}.AddFlat(keys)...))
+}
+
+// MSet 一次设置多个key的值
+func (r *redisClient) MSet(values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	_, err := conn.Do("MSET", redis.Args{}.AddFlat(values)...)
+	return err
+}
+
 // Pipeline 批量提交命令
 func (r *redisClient) Pipeline(commands []*intf.RedisCommand) (reply interface{}, err error) {
 	conn := r.pool.Get()
@@ -196,6 +283,9 @@ func (r *redisClient) Pipeline(commands []*intf.RedisCommand) (reply interface{}
 
 // Shutdown 关闭redis client
 func (r *redisClient) Shutdown() {
+	if r.replicas != nil {
+		r.replicas.close()
+	}
 	_ = r.pool.Close()
 }
 
@@ -225,7 +315,7 @@ func (r *redisClient) HDels(key string, fields []interface{}) (int, error) {
 
 // HMGet 一次获取多个field的值,返回为二维[]byte
 func (r *redisClient) HMGet(key string, fields []string) ([][]byte, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -244,7 +334,7 @@ func (r *redisClient) HMSet(key string, fieldvalues map[string]interface{}) erro
 
 // HGet 获取某个field的值
 func (r *redisClient) HGet(key string, field any) ([]byte, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -253,7 +343,7 @@ func (r *redisClient) HGet(key string, field any) ([]byte, error) {
 
 // HGetInt 获取某个field的int值
 func (r *redisClient) HGetInt(key string, field string) (int, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -262,7 +352,7 @@ func (r *redisClient) HGetInt(key string, field string) (int, error) {
 
 // HGetInt64 获取某个field的int64值
 func (r *redisClient) HGetInt64(key string, field string) (int64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -271,7 +361,7 @@ func (r *redisClient) HGetInt64(key string, field string) (int64, error) {
 
 // HGetFloat64 获取某个field的float64值
 func (r *redisClient) HGetFloat64(key string, field string) (float64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -280,7 +370,7 @@ func (r *redisClient) HGetFloat64(key string, field string) (float64, error) {
 
 // HGetString 获取某个field的float64值
 func (r *redisClient) HGetString(key string, field string) (string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -289,7 +379,7 @@ func (r *redisClient) HGetString(key string, field string) (string, error) {
 
 // HGetAll 获取所有fields的值
 func (r *redisClient) HGetAll(key string) (map[string]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -311,9 +401,27 @@ func (r *redisClient) HSet(key string, field interface{}, value interface{}) (in
 	return redis.Int(conn.Do("HSET", key, field, s))
 }
 
+// HIncrBy 将某个field的值增加increment, 返回增加后的值
+func (r *redisClient) HIncrBy(key string, field string, increment int64) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("HINCRBY", key, field, increment))
+}
+
+// HIncrByFloat 将某个field的值增加increment, 返回增加后的值
+func (r *redisClient) HIncrByFloat(key string, field string, increment float64) (float64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Float64(conn.Do("HINCRBYFLOAT", key, field, increment))
+}
+
 // HLen 设置某个field的值
 func (r *redisClient) HLen(key string) (int, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -327,7 +435,7 @@ func (r *redisClient) HLen(key string) (int, error) {
 
 // Get 获取某个key的值，返回为[]byte
 func (r *redisClient) Get(key string) ([]byte, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -335,7 +443,7 @@ func (r *redisClient) Get(key string) ([]byte, error) {
 }
 
 func (r *redisClient) GetInt(key string) (int, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -343,7 +451,7 @@ func (r *redisClient) GetInt(key string) (int, error) {
 }
 
 func (r *redisClient) GetInt64(key string) (int64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -351,7 +459,7 @@ func (r *redisClient) GetInt64(key string) (int64, error) {
 }
 
 func (r *redisClient) GetFloat64(key string) (float64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -359,7 +467,7 @@ func (r *redisClient) GetFloat64(key string) (float64, error) {
 }
 
 func (r *redisClient) GetString(key string) (string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -396,13 +504,68 @@ func (r *redisClient) SetEx(key string, value interface{}, expire int) error {
 	return err
 }
 
+// SetNX key不存在时设置key为value, expire>0时同时设置过期时间(单位为秒), 返回是否设置成功
+func (r *redisClient) SetNX(key string, value any, expire int) (bool, error) {
+	return r.SetWith(key, value, &intf.RedisSetOption{Expire: time.Duration(expire) * time.Second, NX: true})
+}
+
+// SetWith 按选项设置key为value, 因为NX/XX条件未满足而未设置时返回false
+func (r *redisClient) SetWith(key string, value any, option *intf.RedisSetOption) (bool, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	_, err := redis.String(conn.Do("SET", getSetArgs(key, value, option)...))
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SetGet 按选项设置key为value并返回旧值, 旧值不存在时返回redis.ErrNil
+func (r *redisClient) SetGet(key string, value any, option *intf.RedisSetOption) ([]byte, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Bytes(conn.Do("SET", getSetArgs(key, value, option).Add("GET")...))
+}
+
+func getSetArgs(key string, value any, option *intf.RedisSetOption) redis.Args {
+	args := redis.Args{}.Add(key, value)
+	if option == nil {
+		return args
+	}
+
+	switch {
+	case option.Expire > 0 && option.Expire%time.Second == 0:
+		args = args.Add("EX", int64(option.Expire/time.Second))
+	case option.Expire > 0:
+		args = args.Add("PX", option.Expire.Milliseconds())
+	case option.KeepTtl:
+		args = args.Add("KEEPTTL")
+	}
+
+	switch {
+	case option.NX:
+		args = args.Add("NX")
+	case option.XX:
+		args = args.Add("XX")
+	}
+	return args
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 // set
 // /////////////////////////////////////////////////////////////////////////////
 
 // SIsMember 检查中成员是否出现在key中
 func (r *redisClient) SIsMember(key string, member interface{}) (bool, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -433,7 +596,7 @@ func (r *redisClient) SRem(key string, members interface{}) error {
 
 // SInter 取不同keys中集合的交集
 func (r *redisClient) SInter(keys []string) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -442,7 +605,7 @@ func (r *redisClient) SInter(keys []string) ([]string, error) {
 
 // SUnion 取不同keys中集合的并集
 func (r *redisClient) SUnion(keys []string) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -451,7 +614,7 @@ func (r *redisClient) SUnion(keys []string) ([]string, error) {
 
 // SDiff 比较不同集合中的不同元素
 func (r *redisClient) SDiff(keys []string) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -460,7 +623,7 @@ func (r *redisClient) SDiff(keys []string) ([]string, error) {
 
 // SMembers 取集合中的成员
 func (r *redisClient) SMembers(key string) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -483,7 +646,7 @@ func (r *redisClient) ZRemRangeByScore(key string, min, max interface{}) error {
 
 // ZRangeByScore get members by score
 func (r *redisClient) ZRangeByScore(key string, min, max interface{}, withScores bool, list *protobuf.ListParam) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -503,7 +666,7 @@ func (r *redisClient) ZRangeByScore(key string, min, max interface{}, withScores
 
 // ZRange get members
 func (r *redisClient) ZRange(key string, min, max int64) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -511,7 +674,7 @@ func (r *redisClient) ZRange(key string, min, max int64) ([]string, error) {
 }
 
 // ZAdd add a member
-func (r *redisClient) ZAdd(key string, score int64, member interface{}) error {
+func (r *redisClient) ZAdd(key string, score float64, member interface{}) error {
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -520,19 +683,18 @@ func (r *redisClient) ZAdd(key string, score int64, member interface{}) error {
 	return err
 }
 
-// ZIncrBy add increment to member's score
-func (r *redisClient) ZIncrBy(key string, increment int64, member interface{}) error {
+// ZIncrBy add increment to member's score, return the new score
+func (r *redisClient) ZIncrBy(key string, increment float64, member interface{}) (float64, error) {
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
-	_, err := conn.Do("ZINCRBY", key, increment, member)
-	return err
+	return redis.Float64(conn.Do("ZINCRBY", key, increment, member))
 }
 
 // ZCard get members total
 func (r *redisClient) ZCard(key string) (int, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -540,12 +702,12 @@ func (r *redisClient) ZCard(key string) (int, error) {
 }
 
 // ZScore get score of member
-func (r *redisClient) ZScore(key string, member interface{}) (int64, error) {
-	conn := r.pool.Get()
+func (r *redisClient) ZScore(key string, member interface{}) (float64, error) {
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
-	return redis.Int64(conn.Do("ZSCORE", key, member))
+	return redis.Float64(conn.Do("ZSCORE", key, member))
 }
 
 // ZInterstore get intersect of set
@@ -566,6 +728,42 @@ func (r *redisClient) ZRem(destKey string, members ...interface{}) (int64, error
 	return redis.Int64(conn.Do("ZREM", redis.Args{}.Add(destKey).AddFlat(members)...))
 }
 
+// ZRevRange get members in descending order of score
+func (r *redisClient) ZRevRange(key string, start, stop int64) ([]string, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Strings(conn.Do("ZREVRANGE", key, start, stop))
+}
+
+// ZRank get rank of member in ascending order of score
+func (r *redisClient) ZRank(key string, member any) (int64, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("ZRANK", key, member))
+}
+
+// ZRevRank get rank of member in descending order of score
+func (r *redisClient) ZRevRank(key string, member any) (int64, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("ZREVRANK", key, member))
+}
+
+// ZCount count members with score between min and max
+func (r *redisClient) ZCount(key string, min, max any) (int64, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("ZCOUNT", key, min, max))
+}
+
 // ///////////////////////////////////////////////////////////
 // list
 // ///////////////////////////////////////////////////////////
@@ -597,16 +795,63 @@ func (r *redisClient) RPop(key string) ([]byte, error) {
 	return redis.Bytes(conn.Do("RPOP", key))
 }
 
-func (r *redisClient) LRangeInt64(key string, start, end int64) ([]int64, error) {
+// LPop 移除列表的第一个元素，返回值为移除的元素
+func (r *redisClient) LPop(key string) ([]byte, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Bytes(conn.Do("LPOP", key))
+}
+
+// BLPop 阻塞移除多个列表中第一个非空列表的第一个元素, timeout<=0表示一直阻塞
+func (r *redisClient) BLPop(timeout time.Duration, keys ...string) (string, []byte, error) {
+	return r.blockingPop("BLPOP", timeout, keys)
+}
+
+// BRPop 阻塞移除多个列表中第一个非空列表的最后一个元素, timeout<=0表示一直阻塞
+func (r *redisClient) BRPop(timeout time.Duration, keys ...string) (string, []byte, error) {
+	return r.blockingPop("BRPOP", timeout, keys)
+}
+
+// LRange 获取列表中指定范围的元素
+func (r *redisClient) LRange(key string, start, end int64) ([][]byte, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.ByteSlices(conn.Do("LRANGE", key, start, end))
+}
+
+// LTrim 只保留列表中指定范围的元素
+func (r *redisClient) LTrim(key string, start, end int64) error {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	_, err := conn.Do("LTRIM", key, start, end)
+	return err
+}
+
+// LRem 删除列表中等于value的元素, count>0从头开始删除count个, count<0从尾开始删除, count=0删除所有, 返回删除的数量
+func (r *redisClient) LRem(key string, count int64, value any) (int64, error) {
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
+	return redis.Int64(conn.Do("LREM", key, count, value))
+}
+
+func (r *redisClient) LRangeInt64(key string, start, end int64) ([]int64, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
 	return redis.Int64s(conn.Do("LRANGE", key, start, end))
 }
 
 func (r *redisClient) LRangeString(key string, start, end int64) ([]string, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
@@ -614,31 +859,75 @@ func (r *redisClient) LRangeString(key string, start, end int64) ([]string, erro
 }
 
 func (r *redisClient) LLen(key string) (int64, error) {
-	conn := r.pool.Get()
+	conn := r.readPool().Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
 	return redis.Int64(conn.Do("LLEN", key))
 }
 
-func (r *redisClient) Eval(scriptContent string, keys []interface{}, args []interface{}) (interface{}, error) {
-	script := redis.NewScript(len(keys), scriptContent)
+func (r *redisClient) blockingPop(cmd string, timeout time.Duration, keys []string) (string, []byte, error) {
+	if len(keys) == 0 {
+		return "", nil, errors.New("empty keys")
+	}
 
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
 	}(conn)
 
-	keyArgs := make([]interface{}, 0)
-	keyArgs = append(keyArgs, keys...)
-	keyArgs = append(keyArgs, args...)
+	// redis的超时时间单位为秒, 0表示一直阻塞
+	seconds := math.Ceil(timeout.Seconds())
+	connTimeout := time.Duration(seconds)*time.Second + readTimeout
+	if timeout <= 0 {
+		seconds, connTimeout = 0, 0
+	}
 
-	reply, err := script.Do(conn, keyArgs...)
+	values, err := redis.Values(redis.DoWithTimeout(conn, connTimeout, cmd, redis.Args{}.AddFlat(keys).Add(seconds)...))
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	return reply, nil
+	var (
+		key   string
+		value []byte
+	)
+	_, err = redis.Scan(values, &key, &value)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimPrefix(key, r.prefix), value, nil
+}
+
+// ///////////////////////////////////////////////////////////
+// script
+// ///////////////////////////////////////////////////////////
+
+// Eval 直接发送脚本内容执行
+func (r *redisClient) Eval(scriptContent string, keys []interface{}, args []interface{}) (interface{}, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return conn.Do("EVAL", redis.Args{}.Add(scriptContent, len(keys)).Add(keys...).Add(args...)...)
+}
+
+// EvalSha 通过脚本的sha1执行已缓存的脚本, 如果脚本未缓存返回NOSCRIPT错误
+func (r *redisClient) EvalSha(sha string, keys []interface{}, args []interface{}) (interface{}, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return conn.Do("EVALSHA", redis.Args{}.Add(sha, len(keys)).Add(keys...).Add(args...)...)
+}
+
+// ScriptLoad 缓存脚本, 返回脚本的sha1
+func (r *redisClient) ScriptLoad(scriptContent string) (string, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.String(conn.Do("SCRIPT", "LOAD", scriptContent))
 }
 
 /////////////////////////////////////////////////////////////
@@ -650,6 +939,14 @@ func (r *redisClient) Eval(scriptContent string, keys []interface{}, args []inte
 // key - the name of the filter
 // item - the item to check for
 func (r *redisClient) BfExists(key string, item string) (exists bool, err error) {
+	if r.bloom != nil {
+		result, err := r.bloom.existsMulti(key, []any{item})
+		if err != nil {
+			return false, err
+		}
+		return result[0] == 1, nil
+	}
+
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -662,6 +959,14 @@ func (r *redisClient) BfExists(key string, item string) (exists bool, err error)
 // key - the name of the filter
 // item - the item to add
 func (r *redisClient) BfAdd(key string, item string) (exists bool, err error) {
+	if r.bloom != nil {
+		result, err := r.bloom.addMulti(key, []any{item})
+		if err != nil {
+			return false, err
+		}
+		return result[0] == 1, nil
+	}
+
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -675,6 +980,10 @@ func (r *redisClient) BfAdd(key string, item string) (exists bool, err error) {
 // error_rate - the desired probability for false positives
 // capacity - the number of entries you intend to add to the filter
 func (r *redisClient) BfReserve(key string, errorRate float64, capacity uint64) (err error) {
+	if r.bloom != nil {
+		return r.bloom.reserve(key, errorRate, capacity)
+	}
+
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -688,6 +997,10 @@ func (r *redisClient) BfReserve(key string, errorRate float64, capacity uint64)
 // key - the name of the filter
 // item - One or more items to add
 func (r *redisClient) BfAddMulti(key string, items []interface{}) ([]int64, error) {
+	if r.bloom != nil {
+		return r.bloom.addMulti(key, items)
+	}
+
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -702,6 +1015,10 @@ func (r *redisClient) BfAddMulti(key string, items []interface{}) ([]int64, erro
 // key - the name of the filter
 // item - one or more items to check
 func (r *redisClient) BfExistsMulti(key string, items []interface{}) ([]int64, error) {
+	if r.bloom != nil {
+		return r.bloom.existsMulti(key, items)
+	}
+
 	conn := r.pool.Get()
 	defer func(conn redis.Conn) {
 		_ = conn.Close()
@@ -0,0 +1,100 @@
+package redigo
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+)
+
+// ///////////////////////////////////////////////////////////
+// hyperloglog
+// ///////////////////////////////////////////////////////////
+
+// PfAdd 添加元素到HyperLogLog, 返回基数估算值是否发生变化
+func (r *redisClient) PfAdd(key string, elements ...any) (bool, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Bool(conn.Do("PFADD", redis.Args{}.Add(key).AddFlat(elements)...))
+}
+
+// PfCount 获取一个或多个HyperLogLog合并后的基数估算值
+func (r *redisClient) PfCount(keys ...string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("PFCOUNT", redis.Args{}.AddFlat(keys)...))
+}
+
+// PfMerge 合并多个HyperLogLog到destKey
+func (r *redisClient) PfMerge(destKey string, sourceKeys ...string) error {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	_, err := conn.Do("PFMERGE", redis.Args{}.Add(destKey).AddFlat(sourceKeys)...)
+	return err
+}
+
+// ///////////////////////////////////////////////////////////
+// bitmap
+// ///////////////////////////////////////////////////////////
+
+// SetBit 设置offset位的值, 返回原来的值
+func (r *redisClient) SetBit(key string, offset int64, value int) (int, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int(conn.Do("SETBIT", key, offset, value))
+}
+
+// GetBit 获取offset位的值
+func (r *redisClient) GetBit(key string, offset int64) (int, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int(conn.Do("GETBIT", key, offset))
+}
+
+// BitCount 统计值为1的位数, byteRange为start, end字节范围
+func (r *redisClient) BitCount(key string, byteRange ...int64) (int64, error) {
+	if len(byteRange) != 0 && len(byteRange) != 2 {
+		return 0, errors.New("byte range must be start and end")
+	}
+
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("BITCOUNT", redis.Args{}.Add(key).AddFlat(byteRange)...))
+}
+
+// BitField 执行BITFIELD子命令, e,g: BitField(key, "INCRBY", "u8", 0, 1, "GET", "u4", 8)
+func (r *redisClient) BitField(key string, operations ...any) ([]*int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	values, err := redis.Values(conn.Do("BITFIELD", redis.Args{}.Add(key).AddFlat(operations)...))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*int64, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+
+		v, err := redis.Int64(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = &v
+	}
+	return results, nil
+}
@@ -0,0 +1,117 @@
+package redigo
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+)
+
+// ///////////////////////////////////////////////////////////
+// geo
+// ///////////////////////////////////////////////////////////
+
+// GeoAdd 添加地理位置, 返回新增的成员数量
+func (r *redisClient) GeoAdd(key string, locations ...*intf.RedisGeoLocation) (int64, error) {
+	if len(locations) == 0 {
+		return 0, nil
+	}
+
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key)
+	for _, loc := range locations {
+		args = args.Add(loc.Longitude, loc.Latitude, loc.Name)
+	}
+	return redis.Int64(conn.Do("GEOADD", args...))
+}
+
+// GeoPos 获取成员的地理位置, 不存在的成员对应的位置为nil
+func (r *redisClient) GeoPos(key string, members ...string) ([]*intf.RedisGeoLocation, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	positions, err := redis.Positions(conn.Do("GEOPOS", redis.Args{}.Add(key).AddFlat(members)...))
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]*intf.RedisGeoLocation, len(positions))
+	for i, pos := range positions {
+		if pos == nil {
+			continue
+		}
+		locations[i] = &intf.RedisGeoLocation{Name: members[i], Longitude: pos[0], Latitude: pos[1]}
+	}
+	return locations, nil
+}
+
+// GeoDist 获取两个成员之间的距离, unit为m, km, mi, ft, 为空时为m
+func (r *redisClient) GeoDist(key string, member1, member2 string, unit string) (float64, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key, member1, member2)
+	if unit != "" {
+		args = args.Add(unit)
+	}
+	return redis.Float64(conn.Do("GEODIST", args...))
+}
+
+// GeoSearch 按距离从近到远查找指定半径内的成员, count<=0表示不限制数量
+func (r *redisClient) GeoSearch(key string, longitude, latitude float64, radius float64, unit string, count int64) ([]*intf.RedisGeoLocation, error) {
+	conn := r.readPool().Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	if unit == "" {
+		unit = "m"
+	}
+
+	args := redis.Args{}.Add(key, "FROMLONLAT", longitude, latitude, "BYRADIUS", radius, unit, "ASC")
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+	args = args.Add("WITHCOORD", "WITHDIST")
+
+	values, err := redis.Values(conn.Do("GEOSEARCH", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个结果为[name, dist, [longitude, latitude]]
+	locations := make([]*intf.RedisGeoLocation, 0, len(values))
+	for _, value := range values {
+		var (
+			loc   intf.RedisGeoLocation
+			coord []any
+		)
+		fields, err := redis.Values(value, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = redis.Scan(fields, &loc.Name, &loc.Dist, &coord)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse geosearch reply")
+		}
+
+		_, err = redis.Scan(coord, &loc.Longitude, &loc.Latitude)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse geosearch coordinate")
+		}
+		locations = append(locations, &loc)
+	}
+	return locations, nil
+}
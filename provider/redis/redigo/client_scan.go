@@ -0,0 +1,215 @@
+package redigo
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultDeleteBatchSize = 500
+)
+
+var (
+	// globEscaper 转义redis glob模式中的特殊字符
+	globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+)
+
+// ///////////////////////////////////////////////////////////
+// scan
+// ///////////////////////////////////////////////////////////
+
+// Scan 通过SCAN游标遍历所有匹配的key, 如果配置了key前缀, 只遍历该前缀下的key并返回去除前缀后的key
+func (r *redisClient) Scan(match string, count int) iter.Seq2[string, error] {
+	if r.prefix != "" {
+		if match == "" {
+			match = "*"
+		}
+		// 前缀中的通配符需要转义, 否则可能匹配到其他前缀下的key
+		match = globEscaper.Replace(r.prefix) + match
+	}
+
+	return func(yield func(string, error) bool) {
+		for items, err := range r.scanPages("SCAN", "", match, count) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(strings.TrimPrefix(item, r.prefix), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// HScan 通过HSCAN游标遍历hash中所有匹配的field
+func (r *redisClient) HScan(key string, match string, count int) iter.Seq2[*intf.RedisHashEntry, error] {
+	return func(yield func(*intf.RedisHashEntry, error) bool) {
+		for items, err := range r.scanPages("HSCAN", key, match, count) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := 0; i+1 < len(items); i += 2 {
+				if !yield(&intf.RedisHashEntry{Field: items[i], Value: items[i+1]}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SScan 通过SSCAN游标遍历集合中所有匹配的成员
+func (r *redisClient) SScan(key string, match string, count int) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for items, err := range r.scanPages("SSCAN", key, match, count) {
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ZScan 通过ZSCAN游标遍历有序集合中所有匹配的成员及其分数
+func (r *redisClient) ZScan(key string, match string, count int) iter.Seq2[*intf.RedisZSetMember, error] {
+	return func(yield func(*intf.RedisZSetMember, error) bool) {
+		for items, err := range r.scanPages("ZSCAN", key, match, count) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := 0; i+1 < len(items); i += 2 {
+				score, err := strconv.ParseFloat(items[i+1], 64)
+				if err != nil {
+					yield(nil, errors.Wrapf(err, "parse zscan score, member: %s", items[i]))
+					return
+				}
+
+				if !yield(&intf.RedisZSetMember{Member: items[i], Score: score}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// DeleteByPattern 通过SCAN找到所有匹配的key, 然后分批UNLINK, 返回删除的key的数量
+func (r *redisClient) DeleteByPattern(pattern string, batchSize int) (int64, error) {
+	if pattern == "" {
+		return 0, errors.New("empty pattern")
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultDeleteBatchSize
+	}
+
+	var total int64
+	batch := make([]string, 0, batchSize)
+	for key, err := range r.Scan(pattern, batchSize) {
+		if err != nil {
+			return total, err
+		}
+
+		batch = append(batch, key)
+		if len(batch) < batchSize {
+			continue
+		}
+
+		n, err := r.unlink(batch)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		batch = batch[:0]
+	}
+
+	if len(batch) > 0 {
+		n, err := r.unlink(batch)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+// scanPages 按游标逐页执行SCAN类命令, 每次返回一页的原始结果, 直到游标归零
+func (r *redisClient) scanPages(cmd string, key string, match string, count int) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		var cursor int64
+		for {
+			next, items, err := r.scanPage(cmd, key, cursor, match, count)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if len(items) > 0 && !yield(items, nil) {
+				return
+			}
+
+			if next == 0 {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+func (r *redisClient) scanPage(cmd string, key string, cursor int64, match string, count int) (int64, []string, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}
+	if key != "" {
+		args = args.Add(key)
+	}
+	args = args.Add(cursor)
+	if match != "" {
+		args = args.Add("MATCH", match)
+	}
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+
+	values, err := redis.Values(conn.Do(cmd, args...))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		next  int64
+		items []string
+	)
+	_, err = redis.Scan(values, &next, &items)
+	if err != nil {
+		return 0, nil, err
+	}
+	return next, items, nil
+}
+
+func (r *redisClient) unlink(keys []string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("UNLINK", redis.Args{}.AddFlat(keys)...))
+}
@@ -0,0 +1,345 @@
+package redigo
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+// ///////////////////////////////////////////////////////////
+// stream
+// ///////////////////////////////////////////////////////////
+
+// XAdd 添加消息到流中, 返回消息id, maxLen>0时会近似裁剪流的长度
+func (r *redisClient) XAdd(key string, fields map[string]any, maxLen int64) (string, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key)
+	if maxLen > 0 {
+		args = args.Add("MAXLEN", "~", maxLen)
+	}
+	args = args.Add("*").AddFlat(fields)
+	return redis.String(conn.Do("XADD", args...))
+}
+
+// XLen 获取流中消息的数量
+func (r *redisClient) XLen(key string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("XLEN", key))
+}
+
+// XRange 获取id范围内的消息, start和end可以为-和+, count<=0表示不限制数量
+func (r *redisClient) XRange(key string, start, end string, count int64) ([]*intf.RedisStreamEntry, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key, start, end)
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+	return parseStreamEntries(conn.Do("XRANGE", args...))
+}
+
+// XDel 删除流中的消息
+func (r *redisClient) XDel(key string, ids ...string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("XDEL", redis.Args{}.Add(key).AddFlat(ids)...))
+}
+
+// XTrim 近似裁剪流的长度, 返回删除的消息数量
+func (r *redisClient) XTrim(key string, maxLen int64) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("XTRIM", key, "MAXLEN", "~", maxLen))
+}
+
+// XRead 从多个流中读取消息, 阻塞超时后返回nil
+func (r *redisClient) XRead(streams map[string]string, count int64, block time.Duration) ([]*intf.RedisStream, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+	if block > 0 {
+		args = args.Add("BLOCK", block.Milliseconds())
+	}
+	args = addStreamsArgs(args, streams)
+	return r.trimStreamNames(parseStreams(redis.DoWithTimeout(conn, block+readTimeout, "XREAD", args...)))
+}
+
+// XReadGroup 以消费组中消费者的身份从多个流中读取消息, 阻塞超时后返回nil
+// 起始id为>时读取从未投递过的消息, 否则读取该消费者已投递但未确认的消息
+func (r *redisClient) XReadGroup(group, consumer string, streams map[string]string, count int64, block time.Duration, noAck bool) ([]*intf.RedisStream, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add("GROUP", group, consumer)
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+	if block > 0 {
+		args = args.Add("BLOCK", block.Milliseconds())
+	}
+	if noAck {
+		args = args.Add("NOACK")
+	}
+	args = addStreamsArgs(args, streams)
+	return r.trimStreamNames(parseStreams(redis.DoWithTimeout(conn, block+readTimeout, "XREADGROUP", args...)))
+}
+
+// XAck 确认消息, 返回确认成功的消息数量
+func (r *redisClient) XAck(key, group string, ids ...string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("XACK", redis.Args{}.Add(key, group).AddFlat(ids)...))
+}
+
+// XPending 获取消费组中已投递但未确认的消息, 可以指定只获取某个消费者的
+func (r *redisClient) XPending(key, group string, start, end string, count int64, consumer ...string) ([]*intf.RedisPendingEntry, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key, group, start, end, count)
+	if len(consumer) > 0 && consumer[0] != "" {
+		args = args.Add(consumer[0])
+	}
+
+	values, err := redis.Values(conn.Do("XPENDING", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*intf.RedisPendingEntry, 0, len(values))
+	for _, v := range values {
+		var (
+			entry  intf.RedisPendingEntry
+			idleMs int64
+		)
+		parts, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = redis.Scan(parts, &entry.Id, &entry.Consumer, &idleMs, &entry.DeliveryCount)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse pending entry")
+		}
+		entry.Idle = time.Duration(idleMs) * time.Millisecond
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// XClaim 将空闲时间超过minIdle的消息转移给指定的消费者
+func (r *redisClient) XClaim(key, group, consumer string, minIdle time.Duration, ids ...string) ([]*intf.RedisStreamEntry, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return parseStreamEntries(conn.Do("XCLAIM", redis.Args{}.Add(key, group, consumer, minIdle.Milliseconds()).AddFlat(ids)...))
+}
+
+// XAutoClaim 从start开始扫描空闲时间超过minIdle的消息并转移给指定的消费者, 返回下次扫描的起始id
+func (r *redisClient) XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int64) (string, []*intf.RedisStreamEntry, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add(key, group, consumer, minIdle.Milliseconds(), start)
+	if count > 0 {
+		args = args.Add("COUNT", count)
+	}
+
+	values, err := redis.Values(conn.Do("XAUTOCLAIM", args...))
+	if err != nil {
+		return "", nil, err
+	}
+
+	// redis 7.0以后会多返回一个已删除消息id的列表
+	if len(values) < 2 {
+		return "", nil, errors.New("invalid xautoclaim reply")
+	}
+
+	next, err := redis.String(values[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries, err := parseStreamEntries(values[1], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return next, entries, nil
+}
+
+// XGroupCreate 创建消费组, 消费组已存在时不报错, mkStream为true时流不存在则自动创建
+func (r *redisClient) XGroupCreate(key, group, start string, mkStream bool) error {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	args := redis.Args{}.Add("CREATE", key, group, start)
+	if mkStream {
+		args = args.Add("MKSTREAM")
+	}
+
+	_, err := conn.Do("XGROUP", args...)
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// XGroupDestroy 删除消费组
+func (r *redisClient) XGroupDestroy(key, group string) error {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	_, err := conn.Do("XGROUP", "DESTROY", key, group)
+	return err
+}
+
+// XGroupSetId 设置消费组的最后投递id
+func (r *redisClient) XGroupSetId(key, group, id string) error {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	_, err := conn.Do("XGROUP", "SETID", key, group, id)
+	return err
+}
+
+// XGroupDelConsumer 删除消费组中的消费者, 返回该消费者未确认的消息数量
+func (r *redisClient) XGroupDelConsumer(key, group, consumer string) (int64, error) {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+	return redis.Int64(conn.Do("XGROUP", "DELCONSUMER", key, group, consumer))
+}
+
+// trimStreamNames 去除返回的流名字中的key前缀
+func (r *redisClient) trimStreamNames(streams []*intf.RedisStream, err error) ([]*intf.RedisStream, error) {
+	if err != nil || r.prefix == "" {
+		return streams, err
+	}
+
+	for _, stream := range streams {
+		stream.Name = strings.TrimPrefix(stream.Name, r.prefix)
+	}
+	return streams, nil
+}
+
+func addStreamsArgs(args redis.Args, streams map[string]string) redis.Args {
+	keys := make([]string, 0, len(streams))
+	ids := make([]string, 0, len(streams))
+	for k, id := range streams {
+		keys = append(keys, k)
+		ids = append(ids, id)
+	}
+	return args.Add("STREAMS").AddFlat(keys).AddFlat(ids)
+}
+
+// parseStreams 解析XREAD/XREADGROUP的返回值: [[name, [[id, [field, value...]]...]]...]
+func parseStreams(reply any, err error) ([]*intf.RedisStream, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	streams := make([]*intf.RedisStream, 0, len(values))
+	for _, v := range values {
+		parts, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parts) != 2 {
+			return nil, errors.New("invalid stream reply")
+		}
+
+		name, err := redis.String(parts[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := parseStreamEntries(parts[1], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, &intf.RedisStream{Name: name, Entries: entries})
+	}
+	return streams, nil
+}
+
+// parseStreamEntries 解析消息列表: [[id, [field, value...]]...]
+func parseStreamEntries(reply any, err error) ([]*intf.RedisStreamEntry, error) {
+	values, err := redis.Values(reply, err)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*intf.RedisStreamEntry, 0, len(values))
+	for _, v := range values {
+		// 已删除的消息
+		if v == nil {
+			continue
+		}
+
+		parts, err := redis.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parts) != 2 {
+			return nil, errors.New("invalid stream entry reply")
+		}
+
+		id, err := redis.String(parts[0], nil)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &intf.RedisStreamEntry{Id: id}
+		if parts[1] != nil {
+			entry.Fields, err = redis.StringMap(parts[1], nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
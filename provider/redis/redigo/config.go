@@ -3,26 +3,44 @@ package redigo
 import (
 	"github.com/hdget/hdsdk/v2/errdef"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
 	"github.com/pkg/errors"
+	"strings"
 )
 
 type redisProviderConfig struct {
 	Default *redisClientConfig   `mapstructure:"default"`
 	Items   []*redisClientConfig `mapstructure:"items"`
+	Shard   *shard.Config        `mapstructure:"shard"` // 租户路由, 按租户id路由到items中的实例
 }
 
 type redisClientConfig struct {
-	Name     string `mapstructure:"name"`
+	Name      string            `mapstructure:"name"`
+	Host      string            `mapstructure:"host"`
+	Port      int               `mapstructure:"port"`
+	Db        int               `mapstructure:"db"`
+	Password  string            `mapstructure:"password"`
+	KeyPrefix string            `mapstructure:"key_prefix"` // key前缀, 支持{app}和{env}占位符, e,g: {app}:{env}:
+	Bloom     *redisBloomConfig `mapstructure:"bloom"`      // 未加载RedisBloom模块时使用位图实现布隆过滤器的参数
+	// 从库, 配置后只读命令路由到从库, 写命令和脚本始终在主库执行
+	Replicas     []*redisReplicaConfig `mapstructure:"replicas"`
+	ReadStrategy string                `mapstructure:"read_strategy"` // 从库选择策略: round_robin(缺省), least_latency
+}
+
+type redisReplicaConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
-	Db       int    `mapstructure:"db"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password"` // 为空时使用主库的密码
 }
 
 const (
 	configSection = "sdk.redis"
 )
 
+var (
+	errEmptyAppOrEnv = errors.New("key prefix placeholder requires app and env")
+)
+
 func newConfig(configProvider intf.ConfigProvider) (*redisProviderConfig, error) {
 	if configProvider == nil {
 		return nil, errdef.ErrInvalidConfig
@@ -43,6 +61,11 @@ func newConfig(configProvider intf.ConfigProvider) (*redisProviderConfig, error)
 		return nil, errors.Wrap(err, "validate redis provider config")
 	}
 
+	err = c.resolveKeyPrefix(configProvider.GetApp(), configProvider.GetEnv())
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve redis key prefix")
+	}
+
 	return c, nil
 }
 
@@ -74,7 +97,7 @@ func (c *redisProviderConfig) validateInstanceConfig(conf *redisClientConfig) er
 		conf.Port = 6379
 	}
 
-	return nil
+	return c.validateReplicaConfig(conf)
 }
 
 func (c *redisProviderConfig) validateExtraInstanceConfig(conf *redisClientConfig) error {
@@ -87,5 +110,40 @@ func (c *redisProviderConfig) validateExtraInstanceConfig(conf *redisClientConfi
 		conf.Port = 6379
 	}
 
+	return c.validateReplicaConfig(conf)
+}
+
+func (c *redisProviderConfig) validateReplicaConfig(conf *redisClientConfig) error {
+	for _, replica := range conf.Replicas {
+		if replica.Host == "" {
+			return errdef.ErrInvalidConfig
+		}
+
+		// setup default config value
+		if replica.Port == 0 {
+			replica.Port = 6379
+		}
+
+		if replica.Password == "" {
+			replica.Password = conf.Password
+		}
+	}
+	return nil
+}
+
+// resolveKeyPrefix 替换key前缀中的{app}和{env}占位符
+func (c *redisProviderConfig) resolveKeyPrefix(app, env string) error {
+	replacer := strings.NewReplacer("{app}", app, "{env}", env)
+	for _, conf := range append([]*redisClientConfig{c.Default}, c.Items...) {
+		if conf == nil || conf.KeyPrefix == "" {
+			continue
+		}
+
+		if (app == "" && strings.Contains(conf.KeyPrefix, "{app}")) || (env == "" && strings.Contains(conf.KeyPrefix, "{env}")) {
+			return errEmptyAppOrEnv
+		}
+
+		conf.KeyPrefix = replacer.Replace(conf.KeyPrefix)
+	}
 	return nil
 }
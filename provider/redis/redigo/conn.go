@@ -0,0 +1,172 @@
+package redigo
+
+import (
+	"fmt"
+	"github.com/gomodule/redigo/redis"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prefixConn 自动给命令中所有的key参数加上前缀, 以便不同应用和环境共享同一个redis
+type prefixConn struct {
+	redis.Conn
+	prefix string
+}
+
+// 不包含key参数的命令
+var noKeyCommands = map[string]struct{}{
+	"":         {},
+	"AUTH":     {},
+	"CLIENT":   {},
+	"CONFIG":   {},
+	"DBSIZE":   {},
+	"DISCARD":  {},
+	"ECHO":     {},
+	"EXEC":     {},
+	"FLUSHALL": {},
+	"FLUSHDB":  {},
+	"INFO":     {},
+	"MODULE":   {},
+	"MULTI":    {},
+	"PING":     {},
+	"PUBLISH":  {},
+	"QUIT":     {},
+	"SCAN":     {},
+	"SCRIPT":   {},
+	"SELECT":   {},
+	"TIME":     {},
+	"UNWATCH":  {},
+}
+
+func newPrefixConn(conn redis.Conn, prefix string) redis.Conn {
+	return &prefixConn{Conn: conn, prefix: prefix}
+}
+
+func (c *prefixConn) Do(commandName string, args ...any) (any, error) {
+	return c.Conn.Do(commandName, c.prefixArgs(commandName, args)...)
+}
+
+func (c *prefixConn) Send(commandName string, args ...any) error {
+	return c.Conn.Send(commandName, c.prefixArgs(commandName, args)...)
+}
+
+func (c *prefixConn) DoWithTimeout(timeout time.Duration, commandName string, args ...any) (any, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, c.prefixArgs(commandName, args)...)
+}
+
+func (c *prefixConn) ReceiveWithTimeout(timeout time.Duration) (any, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// prefixArgs 根据命令找到参数中所有的key并加上前缀
+func (c *prefixConn) prefixArgs(commandName string, args []any) []any {
+	indexes := getKeyIndexes(strings.ToUpper(commandName), args)
+	if len(indexes) == 0 {
+		return args
+	}
+
+	newArgs := make([]any, len(args))
+	copy(newArgs, args)
+	for _, i := range indexes {
+		newArgs[i] = addKeyPrefix(c.prefix, newArgs[i])
+	}
+	return newArgs
+}
+
+// getKeyIndexes 获取命令参数中key的位置
+func getKeyIndexes(cmd string, args []any) []int {
+	if _, exists := noKeyCommands[cmd]; exists || len(args) == 0 {
+		return nil
+	}
+
+	switch cmd {
+	case "DEL", "UNLINK", "EXISTS", "TOUCH", "WATCH", "MGET",
+		"SINTER", "SUNION", "SDIFF", "SINTERSTORE", "SUNIONSTORE", "SDIFFSTORE",
+		"PFCOUNT", "PFMERGE":
+		return indexRange(0, len(args))
+	case "RENAME", "RENAMENX", "RPOPLPUSH", "BRPOPLPUSH", "SMOVE", "LMOVE", "BLMOVE", "COPY", "ZRANGESTORE", "GEOSEARCHSTORE":
+		return indexRange(0, min(2, len(args)))
+	case "BLPOP", "BRPOP", "BZPOPMIN", "BZPOPMAX":
+		return indexRange(0, len(args)-1)
+	case "BITOP":
+		return indexRange(1, len(args))
+	case "MSET", "MSETNX":
+		indexes := make([]int, 0, len(args)/2)
+		for i := 0; i < len(args); i += 2 {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	case "EVAL", "EVALSHA", "EVAL_RO", "EVALSHA_RO", "FCALL", "FCALL_RO":
+		return numKeysIndexes(args, 1)
+	case "ZINTERSTORE", "ZUNIONSTORE", "ZDIFFSTORE":
+		return append([]int{0}, numKeysIndexes(args, 1)...)
+	case "ZINTER", "ZUNION", "ZDIFF", "ZINTERCARD", "SINTERCARD", "LMPOP", "ZMPOP":
+		return numKeysIndexes(args, 0)
+	case "XREAD", "XREADGROUP":
+		return streamsIndexes(args)
+	case "XGROUP", "XINFO", "OBJECT", "MEMORY":
+		// 子命令后面紧跟key
+		if len(args) > 1 {
+			return []int{1}
+		}
+		return nil
+	}
+
+	// 缺省第一个参数为key
+	return []int{0}
+}
+
+// numKeysIndexes args[pos]为key的数量, 之后紧跟着key
+func numKeysIndexes(args []any, pos int) []int {
+	if pos >= len(args) {
+		return nil
+	}
+
+	var s string
+	switch v := args[pos].(type) {
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return indexRange(pos+1, min(pos+1+n, len(args)))
+}
+
+// streamsIndexes STREAMS关键字之后的参数前一半为key, 后一半为id
+func streamsIndexes(args []any) []int {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "STREAMS") {
+			n := (len(args) - i - 1) / 2
+			return indexRange(i+1, i+1+n)
+		}
+	}
+	return nil
+}
+
+func indexRange(start, end int) []int {
+	if end <= start {
+		return nil
+	}
+
+	indexes := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func addKeyPrefix(prefix string, key any) any {
+	switch v := key.(type) {
+	case string:
+		return prefix + v
+	case []byte:
+		return append([]byte(prefix), v...)
+	}
+	return prefix + fmt.Sprint(key)
+}
@@ -1,7 +1,10 @@
 package redigo
 
 import (
+	"context"
+	"github.com/elliotchance/pie/v2"
 	"github.com/hdget/hdsdk/v2/intf"
+	"github.com/hdget/hdsdk/v2/lib/shard"
 	"github.com/pkg/errors"
 )
 
@@ -10,6 +13,7 @@ type redigoProvider struct {
 	config        *redisProviderConfig
 	defaultClient intf.RedisClient            // 缺省redis
 	extraClients  map[string]intf.RedisClient // 额外的redis
+	router        intf.ShardRouter            // 租户路由
 }
 
 func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.RedisProvider, error) {
@@ -38,21 +42,32 @@ func New(configProvider intf.ConfigProvider, logger intf.LoggerProvider) (intf.R
 func (r *redigoProvider) Init(args ...any) error {
 	var err error
 	if r.config.Default != nil {
-		r.defaultClient, err = newRedisClient(r.config.Default)
+		r.defaultClient, err = newRedisClient(r.config.Default, r.logger)
 		if err != nil {
 			return errors.Wrap(err, "init redis default client")
 		}
-		r.logger.Debug("init redis default client", "host", r.config.Default.Host)
+		r.logger.Debug("init redis default client", "host", r.config.Default.Host, "replicas", len(r.config.Default.Replicas))
 	}
 
 	for _, itemConf := range r.config.Items {
-		itemClient, err := newRedisClient(itemConf)
+		itemClient, err := newRedisClient(itemConf, r.logger)
 		if err != nil {
 			return errors.Wrapf(err, "new redis extra client, name: %s", itemConf.Name)
 		}
 
 		r.extraClients[itemConf.Name] = itemClient
-		r.logger.Debug("init redis extra client", "name", itemConf.Name, "host", itemConf.Host)
+		r.logger.Debug("init redis extra client", "name", itemConf.Name, "host", itemConf.Host, "replicas", len(itemConf.Replicas))
+	}
+
+	if r.config.Shard != nil {
+		r.router, err = shard.New(r.config.Shard, pie.Keys(r.extraClients))
+		if err != nil {
+			return errors.Wrap(err, "init redis shard router")
+		}
+
+		if err = shard.Watch(r.router, r.config.Shard, r.logger); err != nil {
+			return errors.Wrap(err, "watch redis shard config")
+		}
 	}
 
 	return nil
@@ -65,3 +80,14 @@ func (r *redigoProvider) My() intf.RedisClient {
 func (r *redigoProvider) By(name string) intf.RedisClient {
 	return r.extraClients[name]
 }
+
+func (r *redigoProvider) ForTenant(ctx context.Context) intf.RedisClient {
+	if name := shard.RouteContext(ctx, r.router); name != "" {
+		return r.By(name)
+	}
+	return r.My()
+}
+
+func (r *redigoProvider) Router() intf.ShardRouter {
+	return r.router
+}
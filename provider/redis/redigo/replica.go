@@ -0,0 +1,135 @@
+package redigo
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// replicaSet 从库连接池集合, 按配置的策略选择从库
+type replicaSet struct {
+	strategy  string
+	replicas  []*replica
+	counter   atomic.Uint64 // round robin计数
+	closeChan chan struct{}
+}
+
+type replica struct {
+	host    string
+	pool    *redis.Pool
+	latency atomic.Int64 // 最近一次PING的延迟(纳秒), 不可用时为math.MaxInt64
+}
+
+const (
+	readStrategyRoundRobin   = "round_robin"
+	readStrategyLeastLatency = "least_latency"
+	replicaProbeInterval     = 5 * time.Second
+)
+
+func newReplicaSet(conf *redisClientConfig) (*replicaSet, error) {
+	strategy := conf.ReadStrategy
+	if strategy == "" {
+		strategy = readStrategyRoundRobin
+	}
+
+	if strategy != readStrategyRoundRobin && strategy != readStrategyLeastLatency {
+		return nil, errors.Errorf("invalid redis read strategy: %s", strategy)
+	}
+
+	rs := &replicaSet{
+		strategy:  strategy,
+		replicas:  make([]*replica, 0, len(conf.Replicas)),
+		closeChan: make(chan struct{}),
+	}
+
+	for _, replicaConf := range conf.Replicas {
+		r := &replica{
+			host: replicaConf.Host,
+			pool: newPool(replicaConf.Host, replicaConf.Port, replicaConf.Password, conf.Db, conf.KeyPrefix),
+		}
+		// 首次检测完成前视为不可用, 读请求回退到主库
+		r.latency.Store(math.MaxInt64)
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	// 在后台检测从库, 从库不可用时不影响启动
+	go rs.run()
+
+	return rs, nil
+}
+
+// pick 选择一个可用的从库, 没有可用的从库时返回nil
+func (rs *replicaSet) pick() *redis.Pool {
+	switch rs.strategy {
+	case readStrategyLeastLatency:
+		var (
+			picked *replica
+			min    int64 = math.MaxInt64
+		)
+		for _, r := range rs.replicas {
+			if latency := r.latency.Load(); latency < min {
+				picked, min = r, latency
+			}
+		}
+		if picked == nil {
+			return nil
+		}
+		return picked.pool
+	default:
+		n := uint64(len(rs.replicas))
+		start := rs.counter.Add(1)
+		for i := uint64(0); i < n; i++ {
+			r := rs.replicas[(start+i)%n]
+			if r.latency.Load() != math.MaxInt64 {
+				return r.pool
+			}
+		}
+		return nil
+	}
+}
+
+// run 定期检测从库的可用性和延迟
+func (rs *replicaSet) run() {
+	rs.probe()
+
+	ticker := time.NewTicker(replicaProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rs.closeChan:
+			return
+		case <-ticker.C:
+			rs.probe()
+		}
+	}
+}
+
+func (rs *replicaSet) probe() {
+	for _, r := range rs.replicas {
+		r.latency.Store(r.ping())
+	}
+}
+
+func (r *replica) ping() int64 {
+	conn := r.pool.Get()
+	defer func(conn redis.Conn) {
+		_ = conn.Close()
+	}(conn)
+
+	start := time.Now()
+	_, err := conn.Do("PING")
+	if err != nil {
+		return math.MaxInt64
+	}
+	return time.Since(start).Nanoseconds()
+}
+
+func (rs *replicaSet) close() {
+	close(rs.closeChan)
+	for _, r := range rs.replicas {
+		_ = r.pool.Close()
+	}
+}
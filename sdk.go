@@ -2,6 +2,7 @@ package hdsdk
 
 import (
 	"context"
+	stdErrors "errors"
 	"github.com/hdget/hdsdk/v2/errdef"
 	"github.com/hdget/hdsdk/v2/intf"
 	"github.com/hdget/hdsdk/v2/provider/config/viper"
@@ -23,8 +24,19 @@ type SdkInstance struct {
 	redis          intf.RedisProvider
 	mq             intf.MessageQueueProvider
 	//graph          intf.GraphProvider
+
+	app           *fx.App
+	shutdownMutex sync.Mutex
+	shutdownHooks []ShutdownHook
+	startupHooks  []StartupHook
 }
 
+// ShutdownHook sdk关闭时执行的函数
+type ShutdownHook func(ctx context.Context) error
+
+// StartupHook 所有能力提供者初始化完成后执行的函数, e,g: 数据库迁移
+type StartupHook func(ctx context.Context) error
+
 var (
 	_instance *SdkInstance
 	once      sync.Once
@@ -107,14 +119,60 @@ func (i *SdkInstance) Initialize(capabilities ...*intf.Capability) error {
 		fxOptions = append(fxOptions, fx.NopLogger)
 	}
 
-	err := fx.New(fxOptions...).Start(context.Background())
+	app := fx.New(fxOptions...)
+	err := app.Start(context.Background())
 	if err != nil {
 		return err
 	}
+	i.app = app
+
+	// 启动步骤失败时停止已经启动的能力提供者
+	for _, hook := range i.startupHooks {
+		if err = hook(context.Background()); err != nil {
+			_ = app.Stop(context.Background())
+			return errors.Wrap(err, "run startup hook")
+		}
+	}
 
 	return nil
 }
 
+// OnStartup 注册Initialize时执行的启动步骤, 按注册顺序执行, 必须在Initialize之前调用
+func (i *SdkInstance) OnStartup(hook StartupHook) *SdkInstance {
+	i.startupHooks = append(i.startupHooks, hook)
+	return i
+}
+
+// OnShutdown 注册sdk关闭时执行的函数, 关闭时按注册的逆序执行
+func (i *SdkInstance) OnShutdown(hook ShutdownHook) {
+	i.shutdownMutex.Lock()
+	defer i.shutdownMutex.Unlock()
+	i.shutdownHooks = append(i.shutdownHooks, hook)
+}
+
+// Shutdown 先按注册的逆序执行所有shutdown hooks, 然后停止所有能力提供者
+func (i *SdkInstance) Shutdown(ctx context.Context) error {
+	i.shutdownMutex.Lock()
+	hooks := i.shutdownHooks
+	i.shutdownHooks = nil
+	i.shutdownMutex.Unlock()
+
+	var errs []error
+	for idx := len(hooks) - 1; idx >= 0; idx-- {
+		if err := hooks[idx](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if i.app != nil {
+		if err := i.app.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stdErrors.Join(errs...)
+}
+
 func newInstance(app, env string, options ...Option) (*SdkInstance, error) {
 	sdkOption := defaultSdkOption
 	for _, apply := range options {
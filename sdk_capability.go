@@ -10,9 +10,9 @@ func Db() intf.DbProvider {
 	return _instance.db
 }
 
+// DbBuilder 每次调用返回绑定sqlizer的新实例, 并发请求之间互不影响
 func DbBuilder(sqlizer intf.Sqlizer) intf.DbBuilderProvider {
-	_instance.dbBuilder.Set(sqlizer)
-	return _instance.dbBuilder
+	return _instance.dbBuilder.With(sqlizer)
 }
 
 func Sqlx() intf.SqlxDbProvider {